@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+func dryRunFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "only print what would be changed",
+	}
+}
+
+func yesFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "do not ask for confirmation",
+	}
+}
+
+// confirm asks question unless --yes was given. When stdin is not a terminal --yes is required.
+func confirm(c *cli.Context, question string) (bool, error) {
+	if c.Bool("yes") {
+		return true, nil
+	}
+
+	ok, err := input.AskForConfirmationWithError(question)
+	if errors.Is(err, input.ErrNotInteractive) {
+		return false, fmt.Errorf("--yes is required when not running in a terminal: %w", err)
+	}
+	return ok, err
+}
+
+// finishedAt is when the task was finished, falling back to when it was last updated for tasks
+// that Reclaim did not record a finish time for.
+func finishedAt(task *reclaim.Task) time.Time {
+	if task.Finished.IsZero() {
+		return task.Updated
+	}
+	return task.Finished
+}
+
+func unarchiveCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "unarchive",
+		Description: "Move archived tasks back to complete, by id, title or the date they were finished",
+		ArgsUsage:   "[task id or title...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "finished-after",
+				Usage: "unarchive every task finished after this time, e.g. 2026-01-01",
+			},
+			&cli.StringFlag{
+				Name:  "finished-before",
+				Usage: "unarchive every task finished before this time, e.g. 2026-02-01",
+			},
+			dryRunFlag(),
+			yesFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			byDate := c.IsSet("finished-after") || c.IsSet("finished-before")
+			if byDate && c.Args().Present() {
+				return errors.New("give either tasks or --finished-after/--finished-before, not both")
+			}
+
+			var tasks []*reclaim.Task
+			var err error
+			if byDate {
+				tasks, err = archivedTasksFinishedBetween(c, env)
+			} else {
+				tasks, err = chooseTasks(c, env, "Which tasks would you like to unarchive", []reclaim.TaskStatus{reclaim.StatusArchived}, func(*reclaim.Task) bool {
+					return true
+				})
+			}
+			if err != nil {
+				return err
+			}
+
+			return applyToTasks(c, env, tasks, "unarchive", func(task *reclaim.Task) error {
+				unarchived, err := env.client.TransitionTask(c.Context, task, reclaim.StatusComplete)
+				if err != nil {
+					return err
+				}
+				return env.printer.Print(unarchived, "unarchived task %d %s", unarchived.Id, unarchived.Title)
+			})
+		},
+	}
+}
+
+func archivedTasksFinishedBetween(c *cli.Context, env *appEnv) ([]*reclaim.Task, error) {
+	var after, before time.Time
+	var err error
+	if c.IsSet("finished-after") {
+		if after, err = env.parseTime(c.Context, c.String("finished-after"), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if c.IsSet("finished-before") {
+		if before, err = env.parseTime(c.Context, c.String("finished-before"), time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	archived, err := env.client.GetTasks(c.Context, reclaim.StatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*reclaim.Task
+	for _, task := range archived {
+		finished := finishedAt(task)
+		if !after.IsZero() && !finished.After(after) {
+			continue
+		}
+		if !before.IsZero() && !finished.Before(before) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func purgeCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "purge",
+		Description: "Delete archived tasks that were finished a long time ago. Deleted tasks are kept in the trash",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "older-than",
+				Usage:    "delete archived tasks finished more than this long ago, e.g. 2y or 18w",
+				Required: true,
+			},
+			dryRunFlag(),
+			yesFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			age, err := parseAge(c.String("older-than"))
+			if err != nil {
+				return err
+			}
+			cutoff := time.Now().Add(-age)
+
+			archived, err := env.client.GetTasks(c.Context, reclaim.StatusArchived)
+			if err != nil {
+				return err
+			}
+
+			var tasks []*reclaim.Task
+			for _, task := range archived {
+				if finishedAt(task).Before(cutoff) {
+					tasks = append(tasks, task)
+				}
+			}
+
+			return applyToTasks(c, env, tasks, "delete", func(task *reclaim.Task) error {
+				if err := env.deleteTask(c.Context, task); err != nil {
+					return err
+				}
+				return env.printer.Print(task, "deleted task %d %s", task.Id, task.Title)
+			})
+		},
+	}
+}
+
+// applyToTasks lists tasks, then unless --dry-run was given confirms and calls apply for each one.
+func applyToTasks(c *cli.Context, env *appEnv, tasks []*reclaim.Task, verb string, apply func(*reclaim.Task) error) error {
+	if len(tasks) == 0 {
+		logrus.Infof("no tasks to %s", verb)
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		for _, task := range tasks {
+			err := env.printer.Print(task, "would %s task %d %s (finished %s)", verb, task.Id, task.Title, finishedAt(task).Local().Format(displayTimeFormat))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, task := range tasks {
+		logrus.Infof("%d %s (finished %s)", task.Id, task.Title, finishedAt(task).Local().Format(displayTimeFormat))
+	}
+	ok, err := confirm(c, fmt.Sprintf("Would you like to %s these %d tasks?", verb, len(tasks)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, task := range tasks {
+		errs = append(errs, apply(task))
+	}
+	return errors.Join(errs...)
+}
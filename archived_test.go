@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func addArchived(srv *reclaimtest.Server, title string, finished time.Time) *reclaim.Task {
+	return srv.AddTask(&reclaim.Task{Title: title, Status: reclaim.StatusArchived, Finished: finished})
+}
+
+func Test_unarchive(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	january := addArchived(srv, "january report", time.Date(2026, 1, 15, 12, 0, 0, 0, time.Local))
+	february := addArchived(srv, "february report", time.Date(2026, 2, 15, 12, 0, 0, 0, time.Local))
+	march := addArchived(srv, "march report", time.Date(2026, 3, 15, 12, 0, 0, 0, time.Local))
+
+	out, err := runAppOutput(t, srv, "--format", "{{.Id}}", "unarchive", "--dry-run", "--finished-after", "2026-02-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2\n3\n", out)
+	assert.Equal(t, reclaim.StatusArchived, srv.Task(february.Id).Status)
+
+	require.NoError(t, runApp(t, srv, "unarchive", "--yes", "--finished-after", "2026-02-01", "--finished-before", "2026-03-01"))
+	assert.Equal(t, reclaim.StatusArchived, srv.Task(january.Id).Status)
+	assert.Equal(t, reclaim.StatusComplete, srv.Task(february.Id).Status)
+	assert.Equal(t, reclaim.StatusArchived, srv.Task(march.Id).Status)
+
+	require.NoError(t, runApp(t, srv, "unarchive", "--yes", "january"))
+	assert.Equal(t, reclaim.StatusComplete, srv.Task(january.Id).Status)
+
+	assert.Error(t, runApp(t, srv, "unarchive", "--yes", "--finished-after", "2026-02-01", "march"))
+}
+
+func Test_purge(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	trashDir := t.TempDir()
+
+	old := addArchived(srv, "old", time.Now().AddDate(-3, 0, 0))
+	recent := addArchived(srv, "recent", time.Now().AddDate(0, -1, 0))
+	complete := srv.AddTask(&reclaim.Task{Title: "complete", Status: reclaim.StatusComplete, Finished: time.Now().AddDate(-3, 0, 0)})
+
+	out, err := runAppOutput(t, srv, "--format", "{{.Title}}", "purge", "--older-than", "2y", "--dry-run")
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", out)
+	assert.NotNil(t, srv.Task(old.Id))
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "purge", "--older-than", "2y", "--yes"))
+	assert.Nil(t, srv.Task(old.Id))
+	assert.NotNil(t, srv.Task(recent.Id))
+	assert.NotNil(t, srv.Task(complete.Id))
+
+	// purged tasks can still be restored from the trash
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "restore", "1"))
+
+	assert.Error(t, runApp(t, srv, "purge"))
+	assert.Error(t, runApp(t, srv, "purge", "--older-than", "ages"))
+}
+
+func Test_purgeWithoutTerminal(t *testing.T) {
+	if input.IsInteractive() {
+		t.Skip("stdin is a terminal")
+	}
+
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	old := addArchived(srv, "old", time.Now().AddDate(-3, 0, 0))
+
+	err := runApp(t, srv, "purge", "--older-than", "2y")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--yes is required")
+	assert.NotNil(t, srv.Task(old.Id))
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/fuzzy"
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/journal"
+	"github.com/petetanton/reclaim-cli/pkg/output"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/trash"
+)
+
+// dedupePlan is what dedupe will do to one group of duplicate tasks: the survivor is kept with
+// the summed chunks and the duplicates are deleted. Exact is false when the titles only matched
+// after normalisation or by similarity. Notes and Due are only set when merging changes them.
+type dedupePlan struct {
+	Title               string                `json:"title"`
+	Exact               bool                  `json:"exact"`
+	KeepId              int                   `json:"keep"`
+	DeleteIds           []int                 `json:"delete"`
+	TimeChunksRemaining reclaim.Chunks        `json:"timeChunksRemaining"`
+	TimeChunksRequired  reclaim.Chunks        `json:"timeChunksRequired"`
+	Notes               *string               `json:"notes,omitempty"`
+	Due                 *reclaim.NullableTime `json:"due,omitempty"`
+
+	Survivor   *reclaim.Task   `json:"-"`
+	Duplicates []*reclaim.Task `json:"-"`
+}
+
+func newDedupePlan(group []*reclaim.Task, strategy mergeStrategy) *dedupePlan {
+	keep := strategy.keep.pick(group)
+	survivor := group[keep]
+	var duplicates []*reclaim.Task
+	duplicates = append(duplicates, group[:keep]...)
+	duplicates = append(duplicates, group[keep+1:]...)
+
+	plan := &dedupePlan{
+		Title:               survivor.Title,
+		Exact:               true,
+		KeepId:              survivor.Id,
+		TimeChunksRemaining: survivor.TimeChunksRemaining,
+		TimeChunksRequired:  survivor.TimeChunksRequired,
+		Survivor:            survivor,
+		Duplicates:          duplicates,
+	}
+	for _, duplicate := range duplicates {
+		plan.DeleteIds = append(plan.DeleteIds, duplicate.Id)
+		plan.Exact = plan.Exact && duplicate.Title == survivor.Title
+		plan.TimeChunksRemaining += duplicate.TimeChunksRemaining
+		plan.TimeChunksRequired += duplicate.TimeChunksRequired
+	}
+
+	// the external keys of deleted tasks are always kept so that creating them again finds the survivor
+	notes := survivor.Notes
+	if strategy.notes {
+		notes = mergeNotes(survivor, duplicates)
+	}
+	if notes = reclaim.WithExternalKeys(notes, duplicates...); notes != survivor.Notes {
+		plan.Notes = &notes
+	}
+	if strategy.earliestDue {
+		if due := earliestDue(group); due.Valid && !due.Time.Equal(survivor.Due.Time) {
+			plan.Due = &due
+		}
+	}
+	return plan
+}
+
+// patch is the change made to the survivor.
+func (p *dedupePlan) patch() reclaim.TaskPatch {
+	return reclaim.TaskPatch{
+		TimeChunksRemaining: reclaim.Ptr(p.TimeChunksRemaining),
+		TimeChunksRequired:  reclaim.Ptr(p.TimeChunksRequired),
+		Notes:               p.Notes,
+		Due:                 p.Due,
+	}
+}
+
+// group is what the journal records for the plan before it is applied.
+func (p *dedupePlan) group() *journal.Group {
+	return &journal.Group{
+		Title:      p.Title,
+		Survivor:   p.Survivor,
+		Patch:      p.patch(),
+		Duplicates: p.Duplicates,
+	}
+}
+
+// titleMatcher decides which titles are duplicates of each other.
+type titleMatcher struct {
+	rules     []fuzzy.Rule
+	algorithm fuzzy.Algorithm
+	// threshold is the lowest similarity of normalised titles that counts as a match, 1 only
+	// matches titles that are equal once normalised.
+	threshold float64
+}
+
+func (m titleMatcher) key(title string) string {
+	return fuzzy.Normalize(title, m.rules...)
+}
+
+func (m titleMatcher) matches(key string, other string) bool {
+	return key == other || (m.threshold < 1 && m.algorithm.Similarity(key, other) >= m.threshold)
+}
+
+// planDedupe groups open tasks whose titles match and picks the survivor of each group with
+// strategy. Each task joins the first group whose first title it matches, so groups
+// do not chain together titles that only match through a third. Plans are sorted by title.
+func planDedupe(tasks []*reclaim.Task, matcher titleMatcher, strategy mergeStrategy) []*dedupePlan {
+	type group struct {
+		key   string
+		tasks []*reclaim.Task
+	}
+
+	var groups []*group
+	for _, task := range tasks {
+		if !task.Status.IsOpen() {
+			continue
+		}
+
+		key := matcher.key(task.Title)
+		var match *group
+		for _, g := range groups {
+			if matcher.matches(g.key, key) {
+				match = g
+				break
+			}
+		}
+		if match == nil {
+			match = &group{key: key}
+			groups = append(groups, match)
+		}
+		match.tasks = append(match.tasks, task)
+	}
+
+	var plans []*dedupePlan
+	for _, g := range groups {
+		if len(g.tasks) > 1 {
+			plans = append(plans, newDedupePlan(g.tasks, strategy))
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].Title < plans[j].Title
+	})
+	return plans
+}
+
+// newTitleMatcher builds a titleMatcher from the --normalize, --algorithm and --similarity flags.
+func newTitleMatcher(c *cli.Context) (titleMatcher, error) {
+	matcher := titleMatcher{threshold: c.Float64("similarity")}
+	if matcher.threshold <= 0 || matcher.threshold > 1 {
+		return titleMatcher{}, fmt.Errorf("--similarity must be greater than 0 and at most 1, got %v", matcher.threshold)
+	}
+
+	var err error
+	if matcher.algorithm, err = fuzzy.ParseAlgorithm(c.String("algorithm")); err != nil {
+		return titleMatcher{}, err
+	}
+	for _, value := range splitValues[string](c.StringSlice("normalize")) {
+		if strings.EqualFold(value, "none") {
+			continue
+		}
+		rule, err := fuzzy.ParseRule(value)
+		if err != nil {
+			return titleMatcher{}, err
+		}
+		matcher.rules = append(matcher.rules, rule)
+	}
+	return matcher, nil
+}
+
+// confirmInexact asks before merging groups whose titles are not exactly equal. Those groups are
+// skipped when there is no terminal to ask on and --yes was not given.
+func confirmInexact(c *cli.Context, plans []*dedupePlan) ([]*dedupePlan, error) {
+	var confirmed []*dedupePlan
+	for _, plan := range plans {
+		if plan.Exact || c.Bool("yes") {
+			confirmed = append(confirmed, plan)
+			continue
+		}
+
+		var titles []string
+		for _, duplicate := range plan.Duplicates {
+			titles = append(titles, fmt.Sprintf("%d %q", duplicate.Id, duplicate.Title))
+		}
+		ok, err := input.AskForConfirmationWithError(fmt.Sprintf("Merge %s into %d %q?", strings.Join(titles, ", "), plan.KeepId, plan.Title))
+		if errors.Is(err, input.ErrNotInteractive) {
+			logrus.Warnf("skipping %q as its titles are not an exact match, use --yes to merge it without a terminal", plan.Title)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			confirmed = append(confirmed, plan)
+		}
+	}
+	return confirmed, nil
+}
+
+func dedupePlanTable(plans []*dedupePlan) output.Table {
+	table := output.Table{
+		Headers: []string{"TITLE", "EXACT", "KEEP", "DELETE", "REMAINING", "REQUIRED", "DUE", "NOTES"},
+		Values:  plans,
+	}
+	if plans == nil {
+		table.Values = []*dedupePlan{}
+	}
+
+	for _, plan := range plans {
+		due := ""
+		if plan.Due != nil {
+			due = plan.Due.Local().Format(displayTimeFormat)
+		}
+		notes := "unchanged"
+		if plan.Notes != nil {
+			notes = "merged"
+		}
+
+		var deleteIds []string
+		for _, id := range plan.DeleteIds {
+			deleteIds = append(deleteIds, strconv.Itoa(id))
+		}
+		table.Rows = append(table.Rows, []string{
+			plan.Title,
+			strconv.FormatBool(plan.Exact),
+			strconv.Itoa(plan.KeepId),
+			strings.Join(deleteIds, " "),
+			plan.TimeChunksRemaining.String(),
+			plan.TimeChunksRequired.String(),
+			due,
+			notes,
+		})
+	}
+	return table
+}
+
+func dedupeCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "dedupe",
+		Description: "Deduplicate tasks with the same or similar names (usually tasks that were created via automation)",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "normalize",
+				Usage: fmt.Sprintf("rules applied to titles before comparing them, from %s, or none", joinRules(fuzzy.Rules)),
+				Value: cli.NewStringSlice(joinRules(fuzzy.DefaultRules)),
+			},
+			&cli.StringFlag{
+				Name:  "algorithm",
+				Usage: fmt.Sprintf("how similar titles are scored, %s or %s", fuzzy.LevenshteinAlgorithm, fuzzy.TokenSetAlgorithm),
+				Value: string(fuzzy.LevenshteinAlgorithm),
+			},
+			&cli.Float64Flag{
+				Name:  "similarity",
+				Usage: "lowest similarity, up to 1, of normalised titles that are treated as duplicates, e.g. 0.9",
+				Value: 1,
+			},
+			&cli.StringFlag{
+				Name:    "keep",
+				Usage:   fmt.Sprintf("which task of each group survives: %s", joinValues(survivorStrategies)),
+				EnvVars: []string{"RECLAIM_DEDUPE_KEEP"},
+				Value:   string(defaultMergeStrategy.keep),
+			},
+			&cli.BoolFlag{
+				Name:    "merge-notes",
+				Usage:   "append the notes of the deleted tasks to the survivor's",
+				EnvVars: []string{"RECLAIM_DEDUPE_MERGE_NOTES"},
+			},
+			&cli.BoolFlag{
+				Name:    "earliest-due",
+				Usage:   "give the survivor the earliest due date of its group",
+				EnvVars: []string{"RECLAIM_DEDUPE_EARLIEST_DUE"},
+			},
+			yesFlag(),
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "print which tasks would be kept and deleted instead of changing anything",
+			},
+			&cli.BoolFlag{
+				Name:  "resume",
+				Usage: "finish the dedupe runs that stopped part way through",
+			},
+			&cli.BoolFlag{
+				Name:  "rollback",
+				Usage: "undo the dedupe runs that stopped part way through, restoring deleted tasks from the trash",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "update survivors that were changed since the dedupe run started, overwriting those changes",
+			},
+			&cli.StringFlag{
+				Name:    "journal-dir",
+				Usage:   "where dedupe records its changes before making them. Defaults to reclaim-cli/journal in the user config directory",
+				EnvVars: []string{"RECLAIM_JOURNAL_DIR"},
+			},
+			outputFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("resume") && c.Bool("rollback") {
+				return errors.New("give either --resume or --rollback, not both")
+			}
+			j, err := openJournal(c)
+			if err != nil {
+				return err
+			}
+			if c.Bool("resume") || c.Bool("rollback") {
+				return recoverDedupe(c.Context, env, j, c.Bool("rollback"), c.Bool("force"))
+			}
+
+			matcher, err := newTitleMatcher(c)
+			if err != nil {
+				return err
+			}
+			strategy, err := newMergeStrategy(c)
+			if err != nil {
+				return err
+			}
+
+			tasks, err := env.client.GetTasks(c.Context)
+			if err != nil {
+				return err
+			}
+
+			logrus.Infof("found %d tasks", len(tasks))
+			plans := planDedupe(tasks, matcher, strategy)
+
+			if c.Bool("dry-run") {
+				return writeValues(c, env, plans, dedupePlanTable(plans))
+			}
+
+			unfinished, err := j.Unfinished()
+			if err != nil {
+				return err
+			}
+			if len(unfinished) > 0 {
+				return fmt.Errorf("dedupe run %s did not finish, run dedupe --resume to finish it or dedupe --rollback to undo it first", unfinished[0].Id)
+			}
+
+			if plans, err = confirmInexact(c, plans); err != nil {
+				return err
+			}
+
+			if len(plans) > 0 {
+				var groups []*journal.Group
+				for _, plan := range plans {
+					groups = append(groups, plan.group())
+				}
+				run, err := j.Begin(groups)
+				if err != nil {
+					return fmt.Errorf("could not record the dedupe run before changing any tasks: %w", err)
+				}
+
+				errs := make([]error, len(run.Groups))
+				wg := sync.WaitGroup{}
+				for i, group := range run.Groups {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						errs[i] = dedupe(c.Context, env, j, run, group, c.Bool("force"))
+					}()
+				}
+				wg.Wait()
+
+				if err := errors.Join(errs...); err != nil {
+					return fmt.Errorf("dedupe run %s did not finish, run dedupe --resume to finish it or dedupe --rollback to undo it: %w", run.Id, err)
+				}
+				if err := j.Finish(run); err != nil {
+					return err
+				}
+			}
+
+			tasks, err = env.client.GetTasks(c.Context)
+			if err != nil {
+				return err
+			}
+
+			for _, task := range tasks {
+				err = removeGitlabTaskIfClosed(c.Context, env, task)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func openJournal(c *cli.Context) (*journal.Journal, error) {
+	dir := c.String("journal-dir")
+	if dir == "" {
+		var err error
+		if dir, err = journal.DefaultDir(); err != nil {
+			return nil, fmt.Errorf("could not find a journal directory, use --journal-dir: %w", err)
+		}
+	}
+	return journal.New(dir), nil
+}
+
+func joinRules(rules []fuzzy.Rule) string {
+	var names []string
+	for _, rule := range rules {
+		names = append(names, string(rule))
+	}
+	return strings.Join(names, ",")
+}
+
+// dedupe applies group, recording each step in the journal once it is done. The survivor is patched
+// before any duplicate is deleted so that an interrupted run never loses the merged chunks, and
+// steps that are already recorded are skipped so that a run can be resumed.
+func dedupe(ctx context.Context, env *appEnv, j *journal.Journal, run *journal.Run, group *journal.Group, force bool) error {
+	logrus.Infof("deduping %d %s", group.Survivor.Id, group.Title)
+	if !group.Patched {
+		if err := checkSurvivor(ctx, env, group, force); err != nil {
+			return err
+		}
+		updatedTask, err := env.client.PatchTask(ctx, group.Survivor.Id, group.Patch)
+		if err != nil {
+			return fmt.Errorf("could not update task %d %s: %w", group.Survivor.Id, group.Title, err)
+		}
+		if err := j.Update(run, func() { group.Patched = true }); err != nil {
+			return err
+		}
+		err = env.printer.Print(updatedTask, "task %s updated with %s remaining", group.Title, updatedTask.TimeChunksRemaining)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, duplicate := range group.Duplicates {
+		if group.IsDeleted(duplicate.Id) {
+			continue
+		}
+		if err := env.deleteTask(ctx, duplicate); err != nil {
+			return fmt.Errorf("could not delete task %d %s: %w", duplicate.Id, duplicate.Title, err)
+		}
+		if err := j.Update(run, func() { group.Deleted = append(group.Deleted, duplicate.Id) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// undedupe rolls group back: deleted duplicates are restored from the trash, with new ids, and the
+// survivor's merged fields are put back.
+func undedupe(ctx context.Context, env *appEnv, j *journal.Journal, run *journal.Run, group *journal.Group, force bool) error {
+	logrus.Infof("rolling back %d %s", group.Survivor.Id, group.Title)
+	bin, err := env.openTrash()
+	if err != nil {
+		return err
+	}
+	for _, taskId := range slices.Clone(group.Deleted) {
+		entry, err := bin.Find(taskId)
+		if errors.Is(err, trash.ErrNotFound) {
+			logrus.Warnf("task %d is no longer in the trash in %s, it may have been restored already", taskId, bin.Dir())
+		} else if err != nil {
+			return err
+		} else if err := restore(ctx, env, entry); err != nil {
+			return err
+		}
+
+		err = j.Update(run, func() {
+			group.Deleted = slices.DeleteFunc(group.Deleted, func(id int) bool { return id == taskId })
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if group.Patched {
+		if err := checkSurvivor(ctx, env, group, force); err != nil {
+			return err
+		}
+		revertedTask, err := env.client.PatchTask(ctx, group.Survivor.Id, group.Revert())
+		if err != nil {
+			return fmt.Errorf("could not revert task %d %s: %w", group.Survivor.Id, group.Title, err)
+		}
+		if err := j.Update(run, func() { group.Patched = false }); err != nil {
+			return err
+		}
+		return env.printer.Print(revertedTask, "task %s reverted to %s remaining", group.Title, revertedTask.TimeChunksRemaining)
+	}
+	return nil
+}
+
+// checkSurvivor makes sure the survivor's fields are still what the journal expects before they are
+// patched, so that work logged or edits made since the run started are not silently overwritten.
+func checkSurvivor(ctx context.Context, env *appEnv, group *journal.Group, force bool) error {
+	current, err := env.client.GetTask(ctx, group.Survivor.Id)
+	if err != nil {
+		return fmt.Errorf("could not check task %d %s: %w", group.Survivor.Id, group.Title, err)
+	}
+
+	changed := group.Changed(current)
+	if len(changed) == 0 {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("task %d %s was changed since the dedupe run started (%s), check it and use --force to overwrite it", group.Survivor.Id, group.Title, strings.Join(changed, ", "))
+	}
+	logrus.Warnf("overwriting %s of task %d %s, which changed since the dedupe run started", strings.Join(changed, ", "), group.Survivor.Id, group.Title)
+	return nil
+}
+
+// recoverDedupe resumes, or with rollback undoes, every unfinished run in the journal. A run is
+// removed from the journal once all of its groups have been recovered.
+func recoverDedupe(ctx context.Context, env *appEnv, j *journal.Journal, rollback bool, force bool) error {
+	runs, err := j.Unfinished()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		logrus.Infof("there are no unfinished dedupe runs in %s", j.Dir())
+		return nil
+	}
+
+	var errs []error
+	for _, run := range runs {
+		var runErrs []error
+		for _, group := range run.Groups {
+			if rollback {
+				runErrs = append(runErrs, undedupe(ctx, env, j, run, group, force))
+			} else if !group.Done() {
+				runErrs = append(runErrs, dedupe(ctx, env, j, run, group, force))
+			}
+		}
+
+		if err := errors.Join(runErrs...); err != nil {
+			errs = append(errs, fmt.Errorf("dedupe run %s: %w", run.Id, err))
+			continue
+		}
+		errs = append(errs, j.Finish(run))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/fuzzy"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_dedupe(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	second := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 3, TimeChunksRemaining: 1})
+	other := srv.AddTask(&reclaim.Task{Title: "something else", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+
+	require.NoError(t, runApp(t, srv, "dedupe"))
+
+	assert.Nil(t, srv.Task(second.Id))
+	assert.NotNil(t, srv.Task(other.Id))
+	survivor := srv.Task(first.Id)
+	require.NotNil(t, survivor)
+	assert.Equal(t, reclaim.Chunks(5), survivor.TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(3), survivor.TimeChunksRemaining)
+}
+
+func Test_dedupeResume(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	journalDir := t.TempDir()
+
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	second := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 3, TimeChunksRemaining: 1})
+	third := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+
+	// nothing is deleted when the survivor cannot be updated
+	srv.Fail(http.MethodPatch, "/api/tasks/1", http.StatusForbidden)
+	err := runApp(t, srv, "dedupe", "--journal-dir", journalDir)
+	assert.ErrorContains(t, err, "dedupe --resume")
+	assert.Len(t, srv.Tasks(), 3)
+	srv.Recover(http.MethodPatch, "/api/tasks/1")
+
+	assert.ErrorContains(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir), "did not finish")
+	require.NoError(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir, "--rollback"))
+
+	srv.Fail(http.MethodDelete, "/api/tasks/3", http.StatusForbidden)
+	assert.Error(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir))
+	assert.Nil(t, srv.Task(second.Id))
+	assert.NotNil(t, srv.Task(third.Id))
+	assert.Equal(t, reclaim.Chunks(4), srv.Task(first.Id).TimeChunksRemaining)
+	srv.Recover(http.MethodDelete, "/api/tasks/3")
+
+	require.NoError(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir, "--resume"))
+	assert.Nil(t, srv.Task(third.Id))
+	survivor := srv.Task(first.Id)
+	require.NotNil(t, survivor)
+	assert.Equal(t, reclaim.Chunks(6), survivor.TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(4), survivor.TimeChunksRemaining)
+
+	// the finished run is no longer in the journal
+	require.NoError(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir, "--resume"))
+	require.NoError(t, runApp(t, srv, "dedupe", "--journal-dir", journalDir))
+	assert.Len(t, srv.Tasks(), 1)
+
+	assert.Error(t, runApp(t, srv, "dedupe", "--resume", "--rollback"))
+}
+
+func Test_dedupeRollback(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	journalDir := t.TempDir()
+	trashDir := t.TempDir()
+
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", Notes: "from the MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	second := srv.AddTask(&reclaim.Task{Title: "review MR", Notes: "from a colleague", TimeChunksRequired: 3, TimeChunksRemaining: 1})
+	third := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+
+	srv.Fail(http.MethodDelete, "/api/tasks/3", http.StatusForbidden)
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--merge-notes"))
+	assert.Nil(t, srv.Task(second.Id))
+	assert.Equal(t, "from the MR\n\nfrom a colleague", srv.Task(first.Id).Notes)
+	srv.Recover(http.MethodDelete, "/api/tasks/3")
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--rollback"))
+	survivor := srv.Task(first.Id)
+	require.NotNil(t, survivor)
+	assert.Equal(t, "from the MR", survivor.Notes)
+	assert.Equal(t, reclaim.Chunks(2), survivor.TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(2), survivor.TimeChunksRemaining)
+	assert.NotNil(t, srv.Task(third.Id))
+
+	tasks := srv.Tasks()
+	require.Len(t, tasks, 3)
+	restored := tasks[2]
+	assert.Equal(t, "review MR", restored.Title)
+	assert.Equal(t, "from a colleague", restored.Notes)
+	assert.Equal(t, reclaim.Chunks(1), restored.TimeChunksRemaining)
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--rollback"))
+	assert.Len(t, srv.Tasks(), 3)
+}
+
+func Test_dedupeSurvivorChanged(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	journalDir := t.TempDir()
+	trashDir := t.TempDir()
+	ctx := context.Background()
+
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 3, TimeChunksRemaining: 1})
+
+	// work is logged on the survivor after the run failed to patch it
+	srv.Fail(http.MethodPatch, "/api/tasks/1", http.StatusForbidden)
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir))
+	srv.Recover(http.MethodPatch, "/api/tasks/1")
+	_, err := srv.Client().LogWork(ctx, first.Id, time.Minute*15, time.Time{})
+	require.NoError(t, err)
+
+	err = runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--resume")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was changed since the dedupe run started (timeChunksRemaining)")
+	assert.Equal(t, reclaim.Chunks(1), srv.Task(first.Id).TimeChunksRemaining)
+	assert.Len(t, srv.Tasks(), 2)
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--resume", "--force"))
+	assert.Equal(t, reclaim.Chunks(3), srv.Task(first.Id).TimeChunksRemaining)
+	assert.Len(t, srv.Tasks(), 1)
+
+	// the survivor is edited after it was patched but before the run is rolled back
+	second := srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+	srv.Fail(http.MethodDelete, fmt.Sprintf("/api/tasks/%d", second.Id), http.StatusForbidden)
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir))
+	srv.Recover(http.MethodDelete, fmt.Sprintf("/api/tasks/%d", second.Id))
+	assert.Equal(t, reclaim.Chunks(4), srv.Task(first.Id).TimeChunksRemaining)
+	_, err = srv.Client().PatchTask(ctx, first.Id, reclaim.TaskPatch{TimeChunksRequired: reclaim.Ptr(reclaim.Chunks(10))})
+	require.NoError(t, err)
+
+	err = runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--rollback")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "(timeChunksRequired)")
+	assert.Equal(t, reclaim.Chunks(10), srv.Task(first.Id).TimeChunksRequired)
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "dedupe", "--journal-dir", journalDir, "--rollback", "--force"))
+	assert.Equal(t, reclaim.Chunks(3), srv.Task(first.Id).TimeChunksRemaining)
+	assert.Len(t, srv.Tasks(), 2)
+}
+
+func Test_dedupeKeepsExternalKeys(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	ctx := context.Background()
+
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", Notes: reclaim.WithExternalKey("first", "mr-101"), TimeChunksRemaining: 1})
+	srv.AddTask(&reclaim.Task{Title: "review MR", Notes: reclaim.WithExternalKey("second", "mr-102"), TimeChunksRemaining: 1})
+
+	require.NoError(t, runApp(t, srv, "dedupe"))
+	survivor := srv.Task(first.Id)
+	assert.Equal(t, "first\n\nreclaim-cli-key: mr-101\nreclaim-cli-key: mr-102", survivor.Notes)
+
+	task, created, err := srv.Client().CreateTaskIfNotExists(ctx, reclaim.TaskCreateOptions{Title: "review MR", ExternalKey: "mr-102"}, false)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, first.Id, task.Id)
+}
+
+func Test_dedupeDryRun(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 3, TimeChunksRemaining: 1})
+	srv.AddTask(&reclaim.Task{Title: "something else", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+	srv.AddTask(&reclaim.Task{Title: "review MR", TimeChunksRequired: 4, TimeChunksRemaining: 4})
+	srv.AddTask(&reclaim.Task{Title: "review MR", Status: reclaim.StatusComplete})
+
+	out, err := runAppOutput(t, srv, "dedupe", "--dry-run", "--output", "csv")
+	require.NoError(t, err)
+	assert.Equal(t, "TITLE,EXACT,KEEP,DELETE,REMAINING,REQUIRED,DUE,NOTES\nreview MR,true,1,2 4,1h45m,2h15m,,unchanged\n", out)
+
+	out, err = runAppOutput(t, srv, "dedupe", "--dry-run", "--output", "json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"title": "review MR", "exact": true, "keep": 1, "delete": [2, 4], "timeChunksRemaining": 7, "timeChunksRequired": 9}]`, out)
+
+	assert.Len(t, srv.Tasks(), 5)
+}
+
+func Test_planDedupe(t *testing.T) {
+	tasks := []*reclaim.Task{
+		{Id: 1, Title: "Review MR !101", Status: reclaim.StatusNew},
+		{Id: 2, Title: "review   mr !101 🚀", Status: reclaim.StatusNew},
+		{Id: 3, Title: "Review MR !102", Status: reclaim.StatusNew},
+		{Id: 4, Title: "Deploy service", Status: reclaim.StatusNew},
+		{Id: 5, Title: "Deploy services", Status: reclaim.StatusNew},
+		{Id: 6, Title: "deploy service", Status: reclaim.StatusComplete},
+		{Id: 7, Title: "Write docs", Status: reclaim.StatusNew},
+	}
+
+	tests := []struct {
+		name    string
+		matcher titleMatcher
+		want    map[int][]int
+	}{
+		{"exact", titleMatcher{threshold: 1}, map[int][]int{}},
+		{"default rules", titleMatcher{rules: fuzzy.DefaultRules, threshold: 1}, map[int][]int{1: {2}}},
+		{"mr number", titleMatcher{rules: []fuzzy.Rule{fuzzy.CaseRule, fuzzy.SpaceRule, fuzzy.EmojiRule, fuzzy.MRNumberRule}, threshold: 1}, map[int][]int{1: {2, 3}}},
+		{"similar", titleMatcher{rules: fuzzy.DefaultRules, algorithm: fuzzy.LevenshteinAlgorithm, threshold: 0.9}, map[int][]int{1: {2, 3}, 4: {5}}},
+		{"token set", titleMatcher{rules: fuzzy.DefaultRules, algorithm: fuzzy.TokenSetAlgorithm, threshold: 0.5}, map[int][]int{1: {2, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[int][]int)
+			for _, plan := range planDedupe(tasks, tt.matcher, defaultMergeStrategy) {
+				assert.False(t, plan.Exact)
+				got[plan.KeepId] = plan.DeleteIds
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dedupeFuzzy(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	first := srv.AddTask(&reclaim.Task{Title: "Review MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	second := srv.AddTask(&reclaim.Task{Title: "review MR ✅", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+
+	// there is no terminal to confirm inexact matches on, so they are skipped
+	require.NoError(t, runApp(t, srv, "dedupe"))
+	assert.NotNil(t, srv.Task(second.Id))
+
+	// without normalisation the titles are not duplicates at all
+	out, err := runAppOutput(t, srv, "dedupe", "--normalize", "none", "--dry-run", "--output", "json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", out)
+
+	require.NoError(t, runApp(t, srv, "dedupe", "--yes"))
+	assert.Nil(t, srv.Task(second.Id))
+	assert.Equal(t, reclaim.Chunks(3), srv.Task(first.Id).TimeChunksRemaining)
+
+	assert.Error(t, runApp(t, srv, "dedupe", "--similarity", "1.5"))
+	assert.Error(t, runApp(t, srv, "dedupe", "--normalize", "stemming"))
+	assert.Error(t, runApp(t, srv, "dedupe", "--algorithm", "soundex"))
+}
+
+func Test_newDedupePlan(t *testing.T) {
+	now := time.Now()
+	early := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 11, 9, 17, 0, 0, 0, time.UTC)
+	group := []*reclaim.Task{
+		{Id: 1, Title: "review", Priority: "P3", Created: now.Add(-time.Hour), TimeChunksSpent: 1, Notes: "first", Due: reclaim.NewNullableTime(late)},
+		{Id: 2, Title: "review", Priority: "P1", Created: now, TimeChunksSpent: 0},
+		{Id: 3, Title: "review", Priority: "P2", Created: now.Add(-time.Hour * 2), TimeChunksSpent: 4, Notes: "third", Due: reclaim.NewNullableTime(early)},
+		{Id: 4, Title: "review", Created: now.Add(-time.Hour * 2), TimeChunksSpent: 4, Notes: "first"},
+	}
+
+	tests := []struct {
+		keep       survivorStrategy
+		wantKeep   int
+		wantDelete []int
+	}{
+		{keepFirst, 1, []int{2, 3, 4}},
+		{keepOldest, 3, []int{1, 2, 4}},
+		{keepHighestPriority, 2, []int{1, 3, 4}},
+		{keepMostProgress, 3, []int{1, 2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.keep), func(t *testing.T) {
+			plan := newDedupePlan(group, mergeStrategy{keep: tt.keep})
+			assert.Equal(t, tt.wantKeep, plan.KeepId)
+			assert.Equal(t, tt.wantDelete, plan.DeleteIds)
+			assert.Nil(t, plan.Notes)
+			assert.Nil(t, plan.Due)
+		})
+	}
+
+	plan := newDedupePlan(group, mergeStrategy{keep: keepHighestPriority, notes: true, earliestDue: true})
+	require.NotNil(t, plan.Notes)
+	assert.Equal(t, "first\n\nthird", *plan.Notes)
+	require.NotNil(t, plan.Due)
+	assert.True(t, early.Equal(plan.Due.Time))
+
+	// nothing changes when the survivor already has the earliest due date and the only notes
+	plan = newDedupePlan(group[2:], mergeStrategy{keep: keepFirst, notes: true, earliestDue: true})
+	assert.Equal(t, "third\n\nfirst", *plan.Notes)
+	assert.Nil(t, plan.Due)
+
+	_, err := parseSurvivorStrategy("newest")
+	assert.Error(t, err)
+}
+
+func Test_dedupeStrategy(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	first := srv.AddTask(&reclaim.Task{Title: "review MR", Priority: "P4", Notes: "from the MR", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	urgent := srv.AddTask(&reclaim.Task{Title: "review MR", Priority: "P1", Notes: "from a colleague", TimeChunksRequired: 1, TimeChunksRemaining: 1, Due: reclaim.NewNullableTime(due)})
+
+	require.NoError(t, runApp(t, srv, "dedupe", "--keep", "highest-priority", "--merge-notes", "--earliest-due"))
+	assert.Nil(t, srv.Task(first.Id))
+	survivor := srv.Task(urgent.Id)
+	require.NotNil(t, survivor)
+	assert.Equal(t, "from a colleague\n\nfrom the MR", survivor.Notes)
+	assert.True(t, due.Equal(survivor.Due.Time))
+	assert.Equal(t, reclaim.Chunks(3), survivor.TimeChunksRemaining)
+
+	assert.Error(t, runApp(t, srv, "dedupe", "--keep", "newest"))
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/output"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/trash"
+)
+
+// openTrash returns the trash, working out its directory the first time it is needed so that
+// commands that never delete anything do not need a config directory.
+func (env *appEnv) openTrash() (*trash.Trash, error) {
+	env.trashOnce.Do(func() {
+		dir := env.trashDir
+		if dir == "" {
+			var err error
+			if dir, err = trash.DefaultDir(); err != nil {
+				env.trashErr = fmt.Errorf("could not find a trash directory, use --trash-dir: %w", err)
+				return
+			}
+		}
+		env.trash = trash.New(dir)
+	})
+	return env.trash, env.trashErr
+}
+
+// deleteTask snapshots task into the trash and then deletes it. The snapshot is removed again if
+// the delete fails so that the trash only holds tasks that are really gone. A task that is not
+// found is treated as deleted and keeps its snapshot, as a retried delete whose first response was
+// lost finds the task already gone.
+func (env *appEnv) deleteTask(ctx context.Context, task *reclaim.Task) error {
+	bin, err := env.openTrash()
+	if err != nil {
+		return err
+	}
+
+	entry, err := bin.Save(task)
+	if err != nil {
+		return fmt.Errorf("could not snapshot task %d before deleting it: %w", task.Id, err)
+	}
+
+	err = env.client.DeleteTask(ctx, task.Id)
+	if reclaim.IsNotFound(err) {
+		logrus.Warnf("task %d was already deleted", task.Id)
+		return nil
+	}
+	if err != nil {
+		if removeErr := bin.Remove(entry); removeErr != nil {
+			logrus.Warnf("could not remove the trash entry for task %d: %v", task.Id, removeErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func deleteCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "delete",
+		Aliases:     []string{"rm"},
+		Description: "Delete tasks, keeping a snapshot in the trash so they can be restored. Quote titles that contain spaces",
+		ArgsUsage:   "[task id or title...]",
+		Action: func(c *cli.Context) error {
+			tasks, err := chooseTasks(c, env, "Which tasks would you like to delete", reclaim.KnownStatuses, func(*reclaim.Task) bool {
+				return true
+			})
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, task := range tasks {
+				err := env.deleteTask(c.Context, task)
+				if err == nil {
+					err = env.printer.Print(task, "task %d deleted, undo with: restore %d", task.Id, task.Id)
+				}
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func restoreCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "restore",
+		Description: "Recreate deleted tasks from the trash. Restored tasks get a new id",
+		ArgsUsage:   "[deleted task id...]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "list the tasks in the trash instead of restoring them",
+			},
+			outputFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("list") {
+				bin, err := env.openTrash()
+				if err != nil {
+					return err
+				}
+				entries, err := bin.List()
+				if err != nil {
+					return err
+				}
+				return writeValues(c, env, entries, trashTable(entries))
+			}
+
+			entries, err := chooseTrashEntries(c, env)
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, entry := range entries {
+				errs = append(errs, restore(c.Context, env, entry))
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func restore(ctx context.Context, env *appEnv, entry *trash.Entry) error {
+	task, err := env.client.CreateTask(ctx, entry.Task.CreateOptions())
+	if err != nil {
+		return err
+	}
+	if entry.Task.Status.IsTerminal() {
+		logrus.Warnf("task %d was %s when it was deleted, it has been restored as a new task", entry.Task.Id, entry.Task.Status)
+	}
+
+	bin, err := env.openTrash()
+	if err != nil {
+		return err
+	}
+	if err := bin.Remove(entry); err != nil {
+		logrus.Warnf("task %d was restored but could not be removed from the trash: %v", entry.Task.Id, err)
+	}
+	return env.printer.Print(task, "task %d restored as %d", entry.Task.Id, task.Id)
+}
+
+// chooseTrashEntries finds the latest snapshot of every task id given as an argument, or asks the
+// user to pick from the trash when there are none.
+func chooseTrashEntries(c *cli.Context, env *appEnv) ([]*trash.Entry, error) {
+	bin, err := env.openTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Args().Present() {
+		var entries []*trash.Entry
+		for _, arg := range c.Args().Slice() {
+			taskId, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, fmt.Errorf("expected the id of a deleted task, got %q", arg)
+			}
+			entry, err := bin.Find(taskId)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+
+	entries, err := bin.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		logrus.Infof("the trash in %s is empty", bin.Dir())
+		return nil, nil
+	}
+
+	var items []string
+	byItem := make(map[string]*trash.Entry)
+	for _, entry := range entries {
+		item := fmt.Sprintf("%d, %s (deleted %s)", entry.Task.Id, entry.Task.Title, entry.DeletedAt.Local().Format(displayTimeFormat))
+		items = append(items, item)
+		byItem[item] = entry
+	}
+
+	selected, err := input.AskMultiSelectWithError("Which tasks would you like to restore", items)
+	if errors.Is(err, input.ErrNotInteractive) {
+		return nil, fmt.Errorf("give deleted task ids as arguments when not running in a terminal: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var chosen []*trash.Entry
+	for _, item := range selected {
+		chosen = append(chosen, byItem[item])
+	}
+	return chosen, nil
+}
+
+func trashTable(entries []*trash.Entry) output.Table {
+	table := output.Table{
+		Headers: []string{"ID", "TITLE", "STATUS", "REMAINING", "DELETED"},
+		Values:  entries,
+	}
+	if entries == nil {
+		table.Values = []*trash.Entry{}
+	}
+
+	for _, entry := range entries {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(entry.Task.Id),
+			entry.Task.Title,
+			string(entry.Task.Status),
+			entry.Task.TimeChunksRemaining.String(),
+			entry.DeletedAt.Local().Format(displayTimeFormat),
+		})
+	}
+	return table
+}
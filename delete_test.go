@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_deleteAndRestore(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	trashDir := t.TempDir()
+
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	task := srv.AddTask(&reclaim.Task{
+		Title:               "Write quarterly report",
+		Notes:               "with graphs",
+		Priority:            "P2",
+		EventCategory:       "PERSONAL",
+		TimeChunksRequired:  8,
+		TimeChunksSpent:     2,
+		TimeChunksRemaining: 6,
+		MinChunkSize:        2,
+		MaxChunkSize:        4,
+		Due:                 reclaim.NewNullableTime(due),
+	})
+	other := srv.AddTask(&reclaim.Task{Title: "something else"})
+
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "delete", "quarterly"))
+	assert.Nil(t, srv.Task(task.Id))
+	assert.NotNil(t, srv.Task(other.Id))
+
+	out, err := runAppOutput(t, srv, "--trash-dir", trashDir, "restore", "--list", "--output", "csv")
+	require.NoError(t, err)
+	assert.Contains(t, out, "1,Write quarterly report,NEW,1h30m,")
+
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "restore", "2"))
+
+	out, err = runAppOutput(t, srv, "--trash-dir", trashDir, "--format", "{{.Id}}", "restore", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", out)
+
+	restored := srv.Task(3)
+	require.NotNil(t, restored)
+	assert.Equal(t, "Write quarterly report", restored.Title)
+	assert.Equal(t, "with graphs", restored.Notes)
+	assert.Equal(t, "P2", restored.Priority)
+	assert.Equal(t, "PERSONAL", restored.EventCategory)
+	assert.Equal(t, reclaim.Chunks(6), restored.TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(2), restored.MinChunkSize)
+	assert.True(t, due.Equal(restored.Due.Time))
+
+	// the snapshot is removed once restored
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "restore", "1"))
+}
+
+func Test_deleteMissing(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	trashDir := t.TempDir()
+
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "delete", "404"))
+
+	out, err := runAppOutput(t, srv, "--trash-dir", trashDir, "restore", "--list", "--output", "json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", out)
+}
+
+func Test_deleteLostResponse(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	trashDir := t.TempDir()
+
+	task := srv.AddTask(&reclaim.Task{Title: "gone already"})
+	srv.AddTask(&reclaim.Task{Title: "forbidden"})
+
+	// the delete went through but its response was lost, so the retry finds nothing
+	srv.Fail(http.MethodDelete, "/api/tasks/1", http.StatusNotFound)
+	require.NoError(t, runApp(t, srv, "--trash-dir", trashDir, "delete", strconv.Itoa(task.Id)))
+
+	// any other failure leaves nothing in the trash
+	srv.Fail(http.MethodDelete, "/api/tasks/2", http.StatusForbidden)
+	assert.Error(t, runApp(t, srv, "--trash-dir", trashDir, "delete", "2"))
+
+	out, err := runAppOutput(t, srv, "--trash-dir", trashDir, "--format", "{{.Task.Id}}", "restore", "--list")
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", out)
+}
+
+func Test_trashWithoutConfigDir(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	srv.AddTask(&reclaim.Task{Title: "keep me"})
+
+	t.Setenv("RECLAIM_API_KEY", "test")
+	t.Setenv("RECLAIM_TRASH_DIR", "")
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	// only commands that delete or restore need the trash
+	require.NoError(t, newApp().Run([]string{"reclaim", "--api-url", srv.URL, "version"}))
+	require.NoError(t, newApp().Run([]string{"reclaim", "--api-url", srv.URL, "list"}))
+
+	err := newApp().Run([]string{"reclaim", "--api-url", srv.URL, "delete", "1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --trash-dir")
+	assert.NotNil(t, srv.Task(1))
+}
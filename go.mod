@@ -9,6 +9,8 @@ require (
 	github.com/urfave/cli/v2 v2.27.5
 	gitlab.com/gitlab-org/api/client-go v0.124.0
 	golang.org/x/term v0.29.0
+	golang.org/x/time v0.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -27,6 +29,4 @@ require (
 	golang.org/x/oauth2 v0.26.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
-	golang.org/x/time v0.10.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+// lifecycleAction describes a command that moves tasks to another status with a planner action.
+type lifecycleAction struct {
+	name        string
+	description string
+	// verb completes "task 12 ..." in the message printed for every task.
+	verb   string
+	target reclaim.TaskStatus
+	// from are the statuses of the tasks offered when no arguments are given.
+	from []reclaim.TaskStatus
+	do   func(ctx context.Context, client *reclaim.Client, taskId int) (*reclaim.Task, error)
+}
+
+var lifecycleActions = []lifecycleAction{
+	{
+		name:        "done",
+		description: "Mark tasks as done, removing any time still scheduled for them",
+		verb:        "marked done",
+		target:      reclaim.StatusComplete,
+		from:        reclaim.OpenStatuses,
+		do: func(ctx context.Context, client *reclaim.Client, taskId int) (*reclaim.Task, error) {
+			return client.MarkTaskDone(ctx, taskId)
+		},
+	},
+	{
+		name:        "start",
+		description: "Start working on tasks now",
+		verb:        "started",
+		target:      reclaim.StatusInProgress,
+		from:        []reclaim.TaskStatus{reclaim.StatusNew, reclaim.StatusScheduled},
+		do: func(ctx context.Context, client *reclaim.Client, taskId int) (*reclaim.Task, error) {
+			return client.StartTask(ctx, taskId)
+		},
+	},
+	{
+		name:        "stop",
+		description: "Stop working on in progress tasks, leaving the rest of them to be scheduled",
+		verb:        "stopped",
+		target:      reclaim.StatusScheduled,
+		from:        []reclaim.TaskStatus{reclaim.StatusInProgress},
+		do: func(ctx context.Context, client *reclaim.Client, taskId int) (*reclaim.Task, error) {
+			return client.StopTask(ctx, taskId)
+		},
+	},
+	{
+		name:        "reopen",
+		description: "Reopen completed or cancelled tasks so that they are scheduled again",
+		verb:        "reopened",
+		target:      reclaim.StatusNew,
+		from:        []reclaim.TaskStatus{reclaim.StatusComplete, reclaim.StatusCancelled},
+		do: func(ctx context.Context, client *reclaim.Client, taskId int) (*reclaim.Task, error) {
+			return client.RestartTask(ctx, taskId)
+		},
+	},
+}
+
+func lifecycleCommands(env *appEnv) []*cli.Command {
+	var commands []*cli.Command
+	for _, action := range lifecycleActions {
+		commands = append(commands, lifecycleCommand(env, action))
+	}
+	return commands
+}
+
+func lifecycleCommand(env *appEnv, action lifecycleAction) *cli.Command {
+	return &cli.Command{
+		Name:        action.name,
+		Description: fmt.Sprintf("%s. Quote titles that contain spaces", action.description),
+		ArgsUsage:   "[task id or title...]",
+		Action: func(c *cli.Context) error {
+			question := fmt.Sprintf("Which tasks would you like to %s", action.name)
+			tasks, err := chooseTasks(c, env, question, action.from, func(task *reclaim.Task) bool {
+				return task.Status.CanTransitionTo(action.target)
+			})
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, task := range tasks {
+				updated, err := action.do(c.Context, env.client, task.Id)
+				if err == nil {
+					err = env.printer.Print(updated, "task %d %s", updated.Id, action.verb)
+				}
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func logCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "log",
+		Description: "Record time spent on a task, e.g. log 'review MR' 45m or log 'review MR' 20 for 20 minutes",
+		ArgsUsage:   "<task id or title> <duration>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "end",
+				Usage: "when the work finished, e.g. today 14:30 or 2026-11-02 17:00. Defaults to now",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 2 {
+				return fmt.Errorf("expected a task and a duration, e.g. log %q 45m", "review MR")
+			}
+
+			worked, err := parseWorkDuration(c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+
+			end := time.Now()
+			if c.IsSet("end") {
+				if end, err = env.parseTime(c.Context, c.String("end"), end); err != nil {
+					return err
+				}
+				if end.After(time.Now()) {
+					return fmt.Errorf("cannot log work ending at %s as it is in the future", end.Format(displayTimeFormat))
+				}
+			}
+
+			task, err := newTaskResolver(env.client, reclaim.KnownStatuses...).Resolve(c.Context, c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			updated, err := env.client.LogWork(c.Context, task.Id, worked, end)
+			if err != nil {
+				return err
+			}
+			return env.printer.Print(updated, "logged %s on task %d, %s remaining", worked, updated.Id, updated.TimeChunksRemaining)
+		},
+	}
+}
+
+// parseWorkDuration accepts a duration such as 1h20m, or a plain number of minutes. Unlike task
+// durations it is not rounded to chunks, the time worked is logged as it is, so it must be a whole
+// number of minutes as that is what Reclaim records.
+func parseWorkDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if minutes, err := strconv.Atoi(value); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected e.g. 45m, 1h20m or a number of minutes", value)
+	}
+	if d%time.Minute != 0 {
+		return 0, fmt.Errorf("cannot log %s, work is logged in whole minutes", d)
+	}
+	return d, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_lifecycle(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	report := srv.AddTask(&reclaim.Task{Title: "Write quarterly report", TimeChunksRequired: 4, TimeChunksRemaining: 4})
+	review := srv.AddTask(&reclaim.Task{Title: "Review MR", TimeChunksRequired: 1, TimeChunksRemaining: 1})
+
+	require.NoError(t, runApp(t, srv, "start", "quarterly"))
+	assert.Equal(t, reclaim.StatusInProgress, srv.Task(report.Id).Status)
+
+	out, err := runAppOutput(t, srv, "--format", "{{.Id}} {{.TimeChunksRemaining}}", "log", "quarterly", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, "1 0m\n", out)
+
+	require.NoError(t, runApp(t, srv, "stop", "quarterly"))
+	assert.Equal(t, reclaim.StatusScheduled, srv.Task(report.Id).Status)
+
+	require.NoError(t, runApp(t, srv, "done", "quarterly", "review"))
+	assert.Equal(t, reclaim.StatusComplete, srv.Task(report.Id).Status)
+	assert.Equal(t, reclaim.StatusComplete, srv.Task(review.Id).Status)
+
+	// completed tasks are no longer open so the title does not resolve, and the id is refused
+	assert.Error(t, runApp(t, srv, "stop", "quarterly"))
+	err = runApp(t, srv, "stop", "1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task 1 is COMPLETE")
+
+	require.NoError(t, runApp(t, srv, "reopen", "review"))
+	assert.Equal(t, reclaim.StatusNew, srv.Task(review.Id).Status)
+}
+
+func Test_logErrors(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Title: "review"})
+
+	assert.Error(t, runApp(t, srv, "log", "review"))
+	assert.Error(t, runApp(t, srv, "log", "review", "a while"))
+	assert.Error(t, runApp(t, srv, "log", "--end", "+1d", "review", "30m"))
+	assert.Error(t, runApp(t, srv, "log", "review", "30s"))
+	err := runApp(t, srv, "log", "review", "1m30s")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "whole minutes")
+	assert.Error(t, runApp(t, srv, "log", "review", "0"))
+	assert.Equal(t, reclaim.Chunks(0), srv.Task(1).TimeChunksSpent)
+}
+
+func Test_logIsNotRounded(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	task := srv.AddTask(&reclaim.Task{Title: "review", TimeChunksRemaining: 4})
+
+	require.NoError(t, runApp(t, srv, "log", "review", "20m"))
+	assert.Equal(t, time.Minute*20, srv.Logged(task.Id))
+	require.NoError(t, runApp(t, srv, "log", "review", "5"))
+	assert.Equal(t, time.Minute*25, srv.Logged(task.Id))
+	require.NoError(t, runApp(t, srv, "log", "review", "1h7m"))
+	assert.Equal(t, time.Minute*92, srv.Logged(task.Id))
+}
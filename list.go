@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/output"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+const displayTimeFormat = "2006-01-02 15:04"
+
+type taskFilter struct {
+	priorities []reclaim.TaskPriority
+	categories []reclaim.EventCategory
+	atRisk     bool
+	title      *regexp.Regexp
+	olderThan  time.Duration
+	newerThan  time.Duration
+	dueBefore  time.Time
+	dueAfter   time.Time
+}
+
+func (f taskFilter) matches(task *reclaim.Task, now time.Time) bool {
+	if len(f.priorities) > 0 && !containsFold(f.priorities, task.Priority) {
+		return false
+	}
+	if len(f.categories) > 0 && !containsFold(f.categories, task.EventCategory) {
+		return false
+	}
+	if f.atRisk && !task.AtRisk {
+		return false
+	}
+	if f.title != nil && !f.title.MatchString(task.Title) {
+		return false
+	}
+	if f.olderThan > 0 && !task.Created.Before(now.Add(-f.olderThan)) {
+		return false
+	}
+	if f.newerThan > 0 && !task.Created.After(now.Add(-f.newerThan)) {
+		return false
+	}
+	if !f.dueBefore.IsZero() && !task.Due.Before(f.dueBefore) {
+		return false
+	}
+	if !f.dueAfter.IsZero() && !task.Due.After(f.dueAfter) {
+		return false
+	}
+	return true
+}
+
+func filterTasks(tasks []*reclaim.Task, filter taskFilter, now time.Time) []*reclaim.Task {
+	var filtered []*reclaim.Task
+	for _, task := range tasks {
+		if filter.matches(task, now) {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+var taskSortKeys = map[string]func(a, b *reclaim.Task) bool{
+	"id":       func(a, b *reclaim.Task) bool { return a.Id < b.Id },
+	"title":    func(a, b *reclaim.Task) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+	"status":   func(a, b *reclaim.Task) bool { return a.Status < b.Status },
+	"priority": func(a, b *reclaim.Task) bool { return a.Priority < b.Priority },
+	"category": func(a, b *reclaim.Task) bool { return a.EventCategory < b.EventCategory },
+	"remaining": func(a, b *reclaim.Task) bool {
+		return a.TimeChunksRemaining < b.TimeChunksRemaining
+	},
+	"created": func(a, b *reclaim.Task) bool { return a.Created.Before(b.Created) },
+	"updated": func(a, b *reclaim.Task) bool { return a.Updated.Before(b.Updated) },
+	// tasks without a due date sort last
+	"due": func(a, b *reclaim.Task) bool {
+		if !a.Due.Valid || !b.Due.Valid {
+			return a.Due.Valid && !b.Due.Valid
+		}
+		return a.Due.Time.Before(b.Due.Time)
+	},
+}
+
+func sortTasks(tasks []*reclaim.Task, key string, reverse bool) error {
+	less, ok := taskSortKeys[strings.ToLower(key)]
+	if !ok {
+		return fmt.Errorf("cannot sort by %q, expected one of %s", key, strings.Join(sortKeyNames(), ", "))
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if reverse {
+			return less(tasks[j], tasks[i])
+		}
+		return less(tasks[i], tasks[j])
+	})
+	return nil
+}
+
+func sortKeyNames() []string {
+	var names []string
+	for name := range taskSortKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// taskTable renders tasks for output.Write.
+func taskTable(tasks []*reclaim.Task) output.Table {
+	table := output.Table{
+		Headers: []string{"ID", "TITLE", "STATUS", "PRIORITY", "CATEGORY", "REMAINING", "DUE", "AT RISK", "CREATED"},
+		Values:  tasks,
+	}
+	if tasks == nil {
+		table.Values = []*reclaim.Task{}
+	}
+
+	for _, task := range tasks {
+		due := ""
+		if task.Due.Valid {
+			due = task.Due.Local().Format(displayTimeFormat)
+		}
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(task.Id),
+			task.Title,
+			string(task.Status),
+			task.Priority,
+			task.EventCategory,
+			task.TimeChunksRemaining.String(),
+			due,
+			strconv.FormatBool(task.AtRisk),
+			task.Created.Local().Format(displayTimeFormat),
+		})
+	}
+	return table
+}
+
+// parseAge parses a duration that may also use d (days), w (weeks) and y (years) units, e.g. 30d or 2y.
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	units := map[string]time.Duration{
+		"d": time.Hour * 24,
+		"w": time.Hour * 24 * 7,
+		"y": time.Hour * 24 * 365,
+	}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			value, err := strconv.ParseFloat(n, 64)
+			if err != nil || value < 0 {
+				return 0, fmt.Errorf("could not parse %q as a duration, expected e.g. 12h, 30d, 2w or 1y", s)
+			}
+			return time.Duration(value * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("could not parse %q as a duration, expected e.g. 12h, 30d, 2w or 1y", s)
+	}
+	return d, nil
+}
+
+func parseStatuses(values []string) ([]reclaim.TaskStatus, error) {
+	if len(values) == 0 {
+		return reclaim.OpenStatuses, nil
+	}
+
+	var statuses []reclaim.TaskStatus
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(v, "all") {
+				return reclaim.KnownStatuses, nil
+			}
+			status, err := reclaim.ParseTaskStatus(v)
+			if err != nil {
+				return nil, err
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+func containsFold[T ~string](values []T, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(string(v), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitValues[T ~string](values []string) []T {
+	var out []T
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, T(v))
+			}
+		}
+	}
+	return out
+}
+
+func outputFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output format: table, json, csv or yaml. Ignored when --format is given",
+		Value:   string(output.TableFormat),
+	}
+}
+
+// writeValues prints values with the --format template if there is one, otherwise as table in
+// the --output format.
+func writeValues[T any](c *cli.Context, env *appEnv, values []T, table output.Table) error {
+	if env.printer.Templated() {
+		var vs []interface{}
+		for _, v := range values {
+			vs = append(vs, v)
+		}
+		return env.printer.PrintAll(vs...)
+	}
+
+	format, err := output.ParseFormat(c.String("output"))
+	if err != nil {
+		return err
+	}
+	return output.Write(c.App.Writer, format, table)
+}
+
+func listCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "list",
+		Aliases:     []string{"ls"},
+		Description: "List tasks, optionally filtered and sorted, as a table, JSON, CSV or YAML",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "status",
+				Usage: "only list tasks with these statuses, or all. Defaults to open tasks",
+			},
+			&cli.StringSliceFlag{
+				Name:  "priority",
+				Usage: "only list tasks with these priorities, e.g. P1,P2",
+			},
+			&cli.StringSliceFlag{
+				Name:  "category",
+				Usage: "only list tasks in these event categories, e.g. WORK",
+			},
+			&cli.BoolFlag{
+				Name:  "at-risk",
+				Usage: "only list tasks that are at risk of missing their due date",
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "only list tasks whose title matches this regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "older-than",
+				Usage: "only list tasks created longer ago than this, e.g. 30d",
+			},
+			&cli.StringFlag{
+				Name:  "newer-than",
+				Usage: "only list tasks created within this long, e.g. 12h",
+			},
+			&cli.StringFlag{
+				Name:  "due-before",
+				Usage: "only list tasks due before this time, e.g. eow or 2026-11-02",
+			},
+			&cli.StringFlag{
+				Name:  "due-after",
+				Usage: "only list tasks due after this time, e.g. tomorrow or 2026-11-02",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: fmt.Sprintf("column to sort by, one of %s", strings.Join(sortKeyNames(), ", ")),
+				Value: "id",
+			},
+			&cli.BoolFlag{
+				Name:  "reverse",
+				Usage: "reverse the sort order",
+			},
+			outputFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			if _, err := output.ParseFormat(c.String("output")); err != nil {
+				return err
+			}
+			if err := sortTasks(nil, c.String("sort"), false); err != nil {
+				return err
+			}
+
+			statuses, err := parseStatuses(c.StringSlice("status"))
+			if err != nil {
+				return err
+			}
+
+			filter := taskFilter{
+				priorities: splitValues[reclaim.TaskPriority](c.StringSlice("priority")),
+				categories: splitValues[reclaim.EventCategory](c.StringSlice("category")),
+				atRisk:     c.Bool("at-risk"),
+			}
+			if c.IsSet("title") {
+				filter.title, err = regexp.Compile(c.String("title"))
+				if err != nil {
+					return fmt.Errorf("invalid title expression: %w", err)
+				}
+			}
+			if c.IsSet("older-than") {
+				if filter.olderThan, err = parseAge(c.String("older-than")); err != nil {
+					return err
+				}
+			}
+			if c.IsSet("newer-than") {
+				if filter.newerThan, err = parseAge(c.String("newer-than")); err != nil {
+					return err
+				}
+			}
+			if c.IsSet("due-before") {
+				if filter.dueBefore, err = env.parseTime(c.Context, c.String("due-before"), time.Now()); err != nil {
+					return err
+				}
+			}
+			if c.IsSet("due-after") {
+				if filter.dueAfter, err = env.parseTime(c.Context, c.String("due-after"), time.Now()); err != nil {
+					return err
+				}
+			}
+
+			tasks, err := env.client.GetTasks(c.Context, statuses...)
+			if err != nil {
+				return err
+			}
+
+			tasks = filterTasks(tasks, filter, time.Now())
+			if err := sortTasks(tasks, c.String("sort"), c.Bool("reverse")); err != nil {
+				return err
+			}
+
+			return writeValues(c, env, tasks, taskTable(tasks))
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_parseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "12h", want: time.Hour * 12},
+		{in: "30d", want: time.Hour * 24 * 30},
+		{in: "2w", want: time.Hour * 24 * 14},
+		{in: "2y", want: time.Hour * 24 * 730},
+		{in: "1.5d", want: time.Hour * 36},
+		{in: "-1d", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAge(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_filterTasks(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tasks := []*reclaim.Task{
+		{Id: 1, Title: "Review MR !42", Priority: "P1", EventCategory: "WORK", Created: now.Add(-time.Hour * 24 * 40), AtRisk: true},
+		{Id: 2, Title: "Buy milk", Priority: "P3", EventCategory: "PERSONAL", Created: now.Add(-time.Hour), Due: reclaim.NewNullableTime(now.Add(time.Hour))},
+		{Id: 3, Title: "Review design doc", Priority: "P2", EventCategory: "WORK", Created: now.Add(-time.Hour * 24 * 2)},
+	}
+
+	ids := func(filter taskFilter) []int {
+		var out []int
+		for _, task := range filterTasks(tasks, filter, now) {
+			out = append(out, task.Id)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, ids(taskFilter{}))
+	assert.Equal(t, []int{1, 3}, ids(taskFilter{title: regexp.MustCompile("^Review")}))
+	assert.Equal(t, []int{2}, ids(taskFilter{priorities: []reclaim.TaskPriority{"p3"}}))
+	assert.Equal(t, []int{1, 3}, ids(taskFilter{categories: []reclaim.EventCategory{"WORK"}}))
+	assert.Equal(t, []int{1}, ids(taskFilter{atRisk: true}))
+	assert.Equal(t, []int{1}, ids(taskFilter{olderThan: time.Hour * 24 * 30}))
+	assert.Equal(t, []int{2, 3}, ids(taskFilter{newerThan: time.Hour * 24 * 7}))
+	assert.Equal(t, []int{2}, ids(taskFilter{dueBefore: now.Add(time.Hour * 24)}))
+}
+
+func Test_list(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Title: "b task", Priority: "P2", TimeChunksRemaining: 6})
+	srv.AddTask(&reclaim.Task{Title: "a task", Priority: "P1", TimeChunksRemaining: 2})
+	srv.AddTask(&reclaim.Task{Title: "done", Status: reclaim.StatusComplete})
+
+	out, err := runAppOutput(t, srv, "list", "--sort", "title")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "ID"))
+	assert.Contains(t, lines[1], "a task")
+	assert.Contains(t, lines[2], "1h30m")
+
+	out, err = runAppOutput(t, srv, "list", "--status", "all", "--output", "json")
+	require.NoError(t, err)
+	var tasks []*reclaim.Task
+	require.NoError(t, json.Unmarshal([]byte(out), &tasks))
+	assert.Len(t, tasks, 3)
+
+	out, err = runAppOutput(t, srv, "list", "--priority", "P1", "-o", "csv")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+
+	_, err = runAppOutput(t, srv, "list", "--sort", "colour")
+	assert.Error(t, err)
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -15,20 +18,120 @@ import (
 	"gitlab.com/gitlab-org/api/client-go"
 
 	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/output"
 	"github.com/petetanton/reclaim-cli/pkg/reclaim"
 	"github.com/petetanton/reclaim-cli/pkg/version"
 )
 
+const defaultMaxChunkMultiplier = 8
+
 const (
-	NOW         = "now"
-	IN_ONE_DAY  = "in 1 day"
-	IN_TWO_DAYS = "in 2 days"
-	IN_ONE_WEEK = "in 1 week"
+	NOW                  = "now"
+	NEXT_WORKING_MORNING = "next working morning"
+	IN_ONE_DAY           = "in 1 day"
+	IN_TWO_DAYS          = "in 2 days"
+	IN_ONE_WEEK          = "in 1 week"
 )
 
 func main() {
-	client := reclaim.New()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := newApp().RunContext(ctx, os.Args)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+func newApp() *cli.App {
+	env := &appEnv{}
 	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "api-url",
+			Usage:   "base URL of the Reclaim API",
+			EnvVars: []string{"RECLAIM_API_URL"},
+			Value:   reclaim.DefaultBaseURL,
+		},
+		&cli.IntFlag{
+			Name:    "max-retries",
+			Usage:   "number of times to retry requests that fail with a transient error",
+			EnvVars: []string{"RECLAIM_MAX_RETRIES"},
+			Value:   reclaim.DefaultRetryPolicy.MaxRetries,
+		},
+		&cli.DurationFlag{
+			Name:    "retry-backoff",
+			Usage:   "initial delay between retries, doubled after every attempt",
+			EnvVars: []string{"RECLAIM_RETRY_BACKOFF"},
+			Value:   reclaim.DefaultRetryPolicy.MinBackoff,
+		},
+		&cli.DurationFlag{
+			Name:    "retry-max-backoff",
+			Usage:   "maximum delay between retries",
+			EnvVars: []string{"RECLAIM_RETRY_MAX_BACKOFF"},
+			Value:   reclaim.DefaultRetryPolicy.MaxBackoff,
+		},
+		&cli.Float64Flag{
+			Name:    "rate-limit",
+			Usage:   "maximum requests per second sent to the API, shared by all workers. 0 disables the limit",
+			EnvVars: []string{"RECLAIM_RATE_LIMIT"},
+			Value:   reclaim.DefaultRateLimit,
+		},
+		&cli.IntFlag{
+			Name:    "rate-burst",
+			Usage:   "number of requests that may be sent at once before the rate limit applies",
+			EnvVars: []string{"RECLAIM_RATE_BURST"},
+			Value:   reclaim.DefaultRateBurst,
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "print results with a Go template instead of log lines, e.g. '{{.Id}} {{.Title}}'",
+		},
+		&cli.DurationFlag{
+			Name:    "timeout",
+			Usage:   "deadline for the whole command, e.g. 5m. 0 disables the deadline",
+			EnvVars: []string{"RECLAIM_TIMEOUT"},
+		},
+		&cli.StringFlag{
+			Name:    "trash-dir",
+			Usage:   "where snapshots of deleted tasks are kept so they can be restored. Defaults to reclaim-cli/trash in the user config directory",
+			EnvVars: []string{"RECLAIM_TRASH_DIR"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "holiday",
+			Usage:   fmt.Sprintf("dates to skip when working out the %s, e.g. 2026-12-25. Can be repeated", NEXT_WORKING_MORNING),
+			EnvVars: []string{"RECLAIM_HOLIDAYS"},
+		},
+	}
+	cancel := context.CancelFunc(func() {})
+	app.Before = func(c *cli.Context) error {
+		if timeout := c.Duration("timeout"); timeout > 0 {
+			c.Context, cancel = context.WithTimeout(c.Context, timeout)
+		}
+
+		env.client = reclaim.New(
+			reclaim.WithBaseURL(c.String("api-url")),
+			reclaim.WithRetryPolicy(reclaim.RetryPolicy{
+				MaxRetries: c.Int("max-retries"),
+				MinBackoff: c.Duration("retry-backoff"),
+				MaxBackoff: c.Duration("retry-max-backoff"),
+			}),
+			reclaim.WithRateLimit(c.Float64("rate-limit"), c.Int("rate-burst")),
+		)
+
+		env.trashDir = c.String("trash-dir")
+
+		var err error
+		if env.holidays, err = parseHolidays(c.StringSlice("holiday")); err != nil {
+			return err
+		}
+		env.printer, err = newPrinter(c.App.Writer, c.String("format"))
+		return err
+	}
+	app.After = func(c *cli.Context) error {
+		cancel()
+		return nil
+	}
 	app.Commands = []*cli.Command{
 		{
 			Name: "create",
@@ -37,118 +140,178 @@ func main() {
 					Name:     "title",
 					Required: true,
 				},
+				&cli.StringFlag{
+					Name:  "notes",
+					Usage: "notes to attach to the task",
+				},
+				&cli.StringFlag{
+					Name:  "due",
+					Usage: "due date, e.g. eow, friday 5pm or 2026-11-02 17:00",
+				},
+				&cli.StringFlag{
+					Name:  "snooze-until",
+					Usage: "do not schedule the task before this time, e.g. tomorrow 9am or 2026-11-02 09:00. Skips the start prompt",
+				},
+				&cli.StringFlag{
+					Name:  "category",
+					Usage: "event category, WORK or PERSONAL",
+					Value: string(reclaim.Work),
+				},
+				&cli.StringFlag{
+					Name:  "time-scheme",
+					Usage: "id of the time scheme (hours) the task should be scheduled in",
+				},
+				&cli.BoolFlag{
+					Name:  "private",
+					Usage: "always mark the scheduled events as private",
+				},
+				&cli.BoolFlag{
+					Name:  "on-deck",
+					Usage: "put the task on deck so it is scheduled as soon as possible",
+				},
+				&cli.StringFlag{
+					Name:  "duration",
+					Usage: "total time needed for the task, e.g. 90m, 1h30m or 2.5h. Rounded to 15 minute chunks. Skips the prompt",
+				},
+				&cli.StringFlag{
+					Name:  "min-chunk",
+					Usage: "shortest block of time to schedule, e.g. 30m. Skips the prompt",
+				},
+				&cli.StringFlag{
+					Name:  "max-chunk",
+					Usage: "longest block of time to schedule, e.g. 2h. Defaults to 8 times the min chunk",
+				},
+				&cli.StringFlag{
+					Name:  "priority",
+					Usage: "P1, P2, P3 or P4. Skips the prompt",
+				},
+				&cli.StringFlag{
+					Name:  "start",
+					Usage: fmt.Sprintf("when to start the task: %q, %q or a time such as tomorrow 9am, next monday or +3d. Skips the prompt", NOW, NEXT_WORKING_MORNING),
+				},
+				&cli.StringFlag{
+					Name:  "external-key",
+					Usage: "id of the task in the system it comes from, e.g. a GitLab MR URL. Kept in the notes, if a task already has it no new task is created. Every task, archived ones included, is fetched to check",
+				},
+				&cli.BoolFlag{
+					Name:  "if-not-exists",
+					Usage: "do not create the task if an open task with the same title exists",
+				},
 			},
 			Action: func(c *cli.Context) error {
-				title := c.String("title")
-				mins := input.AskSelect("how many mins for the task", []string{"15", "30", "45", "60", "90", "120", "180", "240"})
-				minsInt, err := strconv.Atoi(mins)
-				if err != nil {
-					return err
+				opts := reclaim.TaskCreateOptions{
+					Title:         c.String("title"),
+					Notes:         c.String("notes"),
+					EventCategory: reclaim.EventCategory(strings.ToUpper(c.String("category"))),
+					TimeSchemeId:  c.String("time-scheme"),
+					AlwaysPrivate: c.Bool("private"),
+					OnDeck:        c.Bool("on-deck"),
+					ExternalKey:   c.String("external-key"),
 				}
-				minChunk := input.AskSelect("what is the min chunk length for the task", []string{"15", "30", "45", "60"})
-				minChunkInt, err := strconv.Atoi(minChunk)
-				if err != nil {
-					return err
+				if opts.EventCategory != reclaim.Work && opts.EventCategory != reclaim.Personal {
+					return fmt.Errorf("unknown category %s, expected %s or %s", c.String("category"), reclaim.Work, reclaim.Personal)
 				}
 
-				minChunkSize := minChunkInt / 15
-
-				priority := input.AskSelect("what is the priority of the task", []string{"P1", "P2", "P3", "P4"})
-
-				task, err := client.CreateTask(title, minChunkSize, minChunkSize*8, minsInt/15, reclaim.TaskPriority(priority))
+				// check before prompting so that automation re-running create is not asked anything. This
+				// is the only lookup, CreateTask below does not look again
+				existing, err := env.client.FindExistingTask(c.Context, opts, c.Bool("if-not-exists"))
 				if err != nil {
 					return err
 				}
-				logrus.Infof("task %s created with id %d", title, task.Id)
-
-				delay := input.AskSelect("when would you like to start this task", []string{NOW, IN_ONE_DAY, IN_TWO_DAYS, IN_ONE_WEEK})
-				if delay == IN_ONE_DAY {
-					return client.SnoozeTask(task.Id, time.Now().Add(time.Hour*24))
+				if existing != nil {
+					return env.printer.Print(existing, "task %s already exists with id %d", existing.Title, existing.Id)
 				}
-				if delay == IN_TWO_DAYS {
-					return client.SnoozeTask(task.Id, time.Now().Add(time.Hour*24*2))
+				if c.IsSet("due") {
+					due, err := env.parseTime(c.Context, c.String("due"), time.Now())
+					if err != nil {
+						return err
+					}
+					opts.Due = &due
 				}
-				if delay == IN_ONE_WEEK {
-					return client.SnoozeTask(task.Id, time.Now().Add(time.Hour*24*7))
+				if c.IsSet("snooze-until") {
+					snoozeUntil, err := env.parseTime(c.Context, c.String("snooze-until"), time.Now())
+					if err != nil {
+						return err
+					}
+					opts.SnoozeUntil = &snoozeUntil
 				}
-				return nil
-			},
-		},
-		{
-			Name:        "snooze",
-			Description: "Snooze a task so that it is scheduled at a later date",
-			Action: func(c *cli.Context) error {
-				tasks, err := client.GetTasks([]string{})
+
+				duration, err := flagOrAsk(c, "duration", "how long will the task take", []string{"15m", "30m", "45m", "1h", "1h30m", "2h", "3h", "4h"})
 				if err != nil {
 					return err
 				}
-
-				var snoozableItems []string
-				for _, task := range tasks {
-					if task.Status != "COMPLETE" && task.Status != "ARCHIVED" {
-						snoozableItems = append(snoozableItems, fmt.Sprintf("%d, %s", task.Id, task.Title))
-					}
+				if opts.TimeChunksRequired, err = parseChunks("duration", duration); err != nil {
+					return err
 				}
 
-				itemToSnooze := input.AskSelect("Which task would you like to snooze", snoozableItems)
-
-				idToSnooze, err := strconv.Atoi(strings.Split(itemToSnooze, ",")[0])
+				minChunk, err := flagOrAsk(c, "min-chunk", "what is the min chunk length for the task", []string{"15m", "30m", "45m", "1h"})
 				if err != nil {
 					return err
 				}
-
-				return client.SnoozeTask(idToSnooze, time.Now().Add(time.Hour*24))
-			},
-		},
-		{
-			Name:        "dedupe",
-			Description: "Deduplicate tasks with the same name (usually tasks that were created via automation)",
-			Action: func(c *cli.Context) error {
-				tasks, err := client.GetTasks([]string{})
-				if err != nil {
+				if opts.MinChunkSize, err = parseChunks("min chunk", minChunk); err != nil {
 					return err
 				}
 
-				logrus.Infof("found %d tasks", len(tasks))
-
-				taskMap := make(map[string][]*reclaim.Task)
-				for _, task := range tasks {
-					if task.Status != "COMPLETE" && task.Status != "ARCHIVED" {
-						taskMap[task.Title] = append(taskMap[task.Title], task)
+				if c.IsSet("max-chunk") {
+					if opts.MaxChunkSize, err = parseChunks("max chunk", c.String("max-chunk")); err != nil {
+						return err
 					}
-				}
-
-				wg := sync.WaitGroup{}
-				for title, dupeTasks := range taskMap {
-					if len(dupeTasks) > 1 {
-						wg.Add(1)
-
-						go dedupe(client, title, dupeTasks, &wg)
+					if opts.MaxChunkSize < opts.MinChunkSize {
+						return fmt.Errorf("max chunk (%s) cannot be smaller than min chunk (%s)", opts.MaxChunkSize, opts.MinChunkSize)
 					}
+				} else {
+					opts.MaxChunkSize = opts.MinChunkSize * defaultMaxChunkMultiplier
+					logrus.Infof("max chunk defaulting to %s (%d x min chunk), use --max-chunk to change it", opts.MaxChunkSize, defaultMaxChunkMultiplier)
 				}
 
-				wg.Wait()
+				if opts.TimeChunksRequired < opts.MinChunkSize {
+					logrus.Warnf("duration %s is shorter than the min chunk %s, the task will be scheduled in a single %s block", opts.TimeChunksRequired, opts.MinChunkSize, opts.MinChunkSize)
+				}
 
-				tasks, err = client.GetTasks([]string{})
+				priority, err := flagOrAsk(c, "priority", "what is the priority of the task", []string{"P1", "P2", "P3", "P4"})
 				if err != nil {
 					return err
 				}
+				opts.Priority = reclaim.TaskPriority(strings.ToUpper(priority))
+				if !containsFold([]reclaim.TaskPriority{reclaim.P1, reclaim.P2, reclaim.P3, reclaim.P4}, priority) {
+					return fmt.Errorf("unknown priority %s, expected P1, P2, P3 or P4", priority)
+				}
 
-				for _, task := range tasks {
-					err = removeGitlabTaskIfClosed(client, task)
+				if opts.SnoozeUntil == nil {
+					delay, err := flagOrAsk(c, "start", "when would you like to start this task", []string{NOW, NEXT_WORKING_MORNING, IN_ONE_DAY, IN_TWO_DAYS, IN_ONE_WEEK})
 					if err != nil {
 						return err
 					}
+					snoozeUntil, err := env.startTime(c.Context, delay, time.Now())
+					if err != nil {
+						return err
+					}
+					if !snoozeUntil.IsZero() {
+						opts.SnoozeUntil = &snoozeUntil
+					}
 				}
-				return nil
+
+				task, err := env.client.CreateTask(c.Context, opts)
+				if err != nil {
+					return err
+				}
+				return env.printer.Print(task, "task %s created with id %d", task.Title, task.Id)
 			},
 		},
+		snoozeCommand(env),
+		unsnoozeCommand(env),
+		logCommand(env),
+		deleteCommand(env),
+		restoreCommand(env),
+		unarchiveCommand(env),
+		purgeCommand(env),
+		dedupeCommand(env),
 		{
 			Name:        "meeting",
 			Description: "create a meeting",
 			Action: func(c *cli.Context) error {
 
-				links, err := client.GetScheduleLinks()
+				links, err := env.client.GetScheduleLinks(c.Context)
 				if err != nil {
 					return err
 				}
@@ -161,7 +324,7 @@ func main() {
 				}
 				linkName := input.AskSelect("Which scheduling link should we use", linkTitles)
 
-				meetingTime, err := client.GetNextMeetingTime(linkMap[linkName].Id)
+				meetingTime, err := env.client.GetNextMeetingTime(c.Context, linkMap[linkName].Id)
 				if err != nil {
 					return err
 				}
@@ -170,12 +333,31 @@ func main() {
 				inviteEmail := input.AskString("What is the invitee email")
 				meetingTitle := input.AskString("What is the meeting title")
 
-				createdMeeting, err := client.CreateMeeting(inviteeName, inviteEmail, meetingTitle, meetingTime, linkMap[linkName].Id)
+				createdMeeting, err := env.client.CreateMeeting(c.Context, inviteeName, inviteEmail, meetingTitle, meetingTime, linkMap[linkName].Id)
 				if err != nil {
 					return err
 				}
-				logrus.Infof("meeting created: %s", createdMeeting.ConferenceData.JoinUrl)
-				return nil
+				return env.printer.Print(createdMeeting, "meeting created: %s", createdMeeting.ConferenceData.JoinUrl)
+			},
+		},
+		{
+			Name:        "links",
+			Description: "List scheduling links",
+			Flags:       []cli.Flag{outputFlag()},
+			Action: func(c *cli.Context) error {
+				links, err := env.client.GetScheduleLinks(c.Context)
+				if err != nil {
+					return err
+				}
+
+				table := output.Table{
+					Headers: []string{"ID", "TITLE", "SLUG", "ENABLED", "DEFAULT DURATION"},
+					Values:  links,
+				}
+				for _, link := range links {
+					table.Rows = append(table.Rows, []string{link.Id, link.Title, link.Slug, strconv.FormatBool(link.Enabled), fmt.Sprintf("%dm", link.DefaultDuration)})
+				}
+				return writeValues(c, env, links, table)
 			},
 		},
 		{
@@ -191,7 +373,7 @@ func main() {
 				if autoArchiveAge == 0 {
 					autoArchiveAge = 365
 				}
-				tasks, err := client.GetTasks([]string{"COMPLETE"})
+				tasks, err := env.client.GetTasks(c.Context, reclaim.StatusComplete)
 				if err != nil {
 					return err
 				}
@@ -206,9 +388,15 @@ func main() {
 					go func() {
 						defer wg.Done()
 						for task := range taskToArchive {
-							task.Status = "ARCHIVED"
-							_, err := client.UpdateTask(task)
-							output <- err
+							archived, err := env.client.TransitionTask(c.Context, task, reclaim.StatusArchived)
+							if err == nil {
+								err = env.printer.Print(archived, "archived task %d", archived.Id)
+							}
+							select {
+							case output <- err:
+							case <-c.Context.Done():
+								return
+							}
 						}
 					}()
 				}
@@ -220,7 +408,11 @@ func main() {
 							isOldTask := task.Finished.Before(time.Now().Add(time.Hour * 24 * time.Duration(autoArchiveAge) * -1))
 							if isOldTask || input.AskForConfirmation(fmt.Sprintf("Would you like to archive '%s': %v?", task.Title, task.Finished)) {
 								logrus.Infof("Archiving task %d", task.Id)
-								taskToArchive <- task
+								select {
+								case taskToArchive <- task:
+								case <-c.Context.Done():
+									return
+								}
 								if !isOldTask {
 									maxCount -= 1
 								}
@@ -244,6 +436,10 @@ func main() {
 					}
 				}
 
+				if err := c.Context.Err(); err != nil {
+					return err
+				}
+
 				if foundErr {
 					return errors.New("error when archiving tasks - see logs")
 				}
@@ -263,6 +459,7 @@ func main() {
 				},
 			},
 		},
+		listCommand(env),
 		{
 			Name: "version",
 			Action: func(c *cli.Context) error {
@@ -271,40 +468,12 @@ func main() {
 			},
 		},
 	}
+	app.Commands = append(app.Commands, lifecycleCommands(env)...)
 
-	err := app.Run(os.Args)
-	if err != nil {
-		logrus.Fatal(err)
-	}
+	return app
 }
 
-func dedupe(client *reclaim.Client, title string, dupeTasks []*reclaim.Task, wg *sync.WaitGroup) {
-	defer wg.Done()
-	logrus.Infof("deduping %d %s", dupeTasks[0].Id, title)
-	chunksRemaining := 0
-	chunksRequired := 0
-	for i := 1; i < len(dupeTasks); i++ {
-		chunksRemaining += dupeTasks[i].TimeChunksRemaining
-		chunksRequired += dupeTasks[i].TimeChunksRequired
-		err := client.DeleteTask(dupeTasks[i].Id)
-		if err != nil {
-			logrus.Error(err)
-			return
-		}
-	}
-
-	mainTask := dupeTasks[0]
-	mainTask.TimeChunksRemaining += chunksRemaining
-	mainTask.TimeChunksRequired += chunksRequired
-	updatedTask, err := client.UpdateTask(mainTask)
-	if err != nil {
-		logrus.Error(err)
-		return
-	}
-	logrus.Infof("task %s updated with %d chunks remaining", title, updatedTask.TimeChunksRemaining)
-}
-
-func removeGitlabTaskIfClosed(client *reclaim.Client, task *reclaim.Task) error {
+func removeGitlabTaskIfClosed(ctx context.Context, env *appEnv, task *reclaim.Task) error {
 	gitlabUrl := os.Getenv("GITLAB_URL")
 	if gitlabUrl == "" {
 		logrus.Warn("GITLAB_URL not set, skipping gitlab tasks")
@@ -326,13 +495,13 @@ func removeGitlabTaskIfClosed(client *reclaim.Client, task *reclaim.Task) error
 			return err
 		}
 
-		mr, _, err := git.MergeRequests.GetMergeRequest(pid, getLastSegmentAsInt(task.Title), nil)
+		mr, _, err := git.MergeRequests.GetMergeRequest(pid, getLastSegmentAsInt(task.Title), nil, gitlab.WithContext(ctx))
 		if err != nil {
 			return err
 		}
 		if mr.State == "merged" {
 			logrus.Infof("removing task: %s", task.Title)
-			return client.DeleteTask(task.Id)
+			return env.deleteTask(ctx, task)
 		}
 		if mr.State != "opened" {
 			return fmt.Errorf("MR state: %s", mr.State)
@@ -341,6 +510,41 @@ func removeGitlabTaskIfClosed(client *reclaim.Client, task *reclaim.Task) error
 	return nil
 }
 
+// flagOrAsk returns the value of the flag if it was given, otherwise it prompts the user to pick
+// one of options. When stdin is not a terminal input.ErrNotInteractive is returned instead.
+func flagOrAsk(c *cli.Context, flag string, question string, options []string) (string, error) {
+	if c.IsSet(flag) {
+		return c.String(flag), nil
+	}
+
+	value, err := input.AskSelectWithError(question, options)
+	if errors.Is(err, input.ErrNotInteractive) {
+		return "", fmt.Errorf("--%s is required when not running in a terminal: %w", flag, err)
+	}
+	return value, err
+}
+
+// parseChunks parses a duration flag or prompt answer, warning when it has to be rounded.
+func parseChunks(name string, value string) (reclaim.Chunks, error) {
+	chunks, rounded, err := reclaim.ParseChunks(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if rounded {
+		logrus.Warnf("%s %s rounded to %s as Reclaim schedules in %s chunks", name, value, chunks, reclaim.Chunks(1))
+	}
+	return chunks, nil
+}
+
+// startTime converts one of the start choices or a time accepted by parseTime to the time a
+// task should be snoozed until. The zero time means start now.
+func (env *appEnv) startTime(ctx context.Context, delay string, now time.Time) (time.Time, error) {
+	if strings.EqualFold(strings.TrimSpace(delay), NOW) {
+		return time.Time{}, nil
+	}
+	return env.parseTime(ctx, delay, now)
+}
+
 func getLastSegmentAsInt(s string) int {
 	lastSegment := getLastSegment(s)
 	lastSegmentInt, err := strconv.Atoi(strings.TrimRightFunc(lastSegment, func(r rune) bool {
@@ -1,12 +1,112 @@
 package main
 
 import (
+	"bytes"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
 )
 
 func Test_getLastSegmentAsInt(t *testing.T) {
 	assert.Equal(t, 151, getLastSegmentAsInt("http://example.com/151"))
 	assert.Equal(t, 151, getLastSegmentAsInt("http://example.com/151"))
 }
+
+func runApp(t *testing.T, srv *reclaimtest.Server, args ...string) error {
+	t.Helper()
+	_, err := runAppOutput(t, srv, args...)
+	return err
+}
+
+// runAppOutput runs the CLI against srv and returns what it wrote to stdout.
+func runAppOutput(t *testing.T, srv *reclaimtest.Server, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("RECLAIM_API_KEY", "test")
+	t.Setenv("GITLAB_URL", "")
+	t.Setenv("RECLAIM_TRASH_DIR", t.TempDir())
+	t.Setenv("RECLAIM_JOURNAL_DIR", t.TempDir())
+
+	var out bytes.Buffer
+	app := newApp()
+	app.Writer = &out
+	err := app.Run(append([]string{"reclaim", "--api-url", srv.URL}, args...))
+	return out.String(), err
+}
+
+func Test_archive(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	old := srv.AddTask(&reclaim.Task{Title: "old", Status: "COMPLETE", Finished: time.Now().Add(-time.Hour * 24 * 400)})
+	open := srv.AddTask(&reclaim.Task{Title: "open"})
+
+	require.NoError(t, runApp(t, srv, "archive", "--auto-archive-age", "365", "--max-count", "0"))
+
+	assert.Equal(t, reclaim.StatusArchived, srv.Task(old.Id).Status)
+	assert.Equal(t, reclaim.StatusNew, srv.Task(open.Id).Status)
+}
+
+func Test_create(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	require.NoError(t, runApp(t, srv, "create", "--title", `fix "quoted" bug`, "--duration", "1h30m", "--min-chunk", "30m", "--max-chunk", "1h", "--priority", "p2", "--start", "in 1 day"))
+
+	tasks := srv.Tasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, `fix "quoted" bug`, tasks[0].Title)
+	assert.Equal(t, reclaim.Chunks(6), tasks[0].TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(2), tasks[0].MinChunkSize)
+	assert.Equal(t, reclaim.Chunks(4), tasks[0].MaxChunkSize)
+	assert.Equal(t, "P2", tasks[0].Priority)
+	assert.True(t, tasks[0].SnoozeUntil.After(time.Now().Add(time.Hour*23)))
+}
+
+func Test_createExisting(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	key := "https://gitlab.example.com/group/project/-/merge_requests/101"
+	args := []string{"--format", "{{.Id}}", "create", "--title", "review MR !101", "--external-key", key, "--duration", "30m", "--min-chunk", "15m", "--priority", "P2", "--start", "now"}
+	out, err := runAppOutput(t, srv, args...)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", out)
+
+	// nothing is prompted for when the task exists, so only the key and title are needed
+	out, err = runAppOutput(t, srv, "--format", "{{.Id}}", "create", "--title", "review MR !101", "--external-key", key)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", out)
+	assert.Equal(t, 2, srv.Requests(http.MethodGet, "/api/tasks"), "each create looks for the key once")
+
+	out, err = runAppOutput(t, srv, "--format", "{{.Id}}", "create", "--title", "Review MR !101", "--if-not-exists")
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", out)
+
+	tasks := srv.Tasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, []string{key}, tasks[0].ExternalKeys())
+}
+
+func Test_createWithoutTerminal(t *testing.T) {
+	if input.IsInteractive() {
+		t.Skip("stdin is a terminal")
+	}
+
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	err := runApp(t, srv, "create", "--title", "needs input", "--min-chunk", "15m", "--priority", "P1", "--start", "now")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--duration is required")
+
+	err = runApp(t, srv, "create", "--title", "bad duration", "--duration", "a while", "--min-chunk", "15m", "--priority", "P1", "--start", "now")
+	require.Error(t, err)
+	assert.Empty(t, srv.Tasks())
+}
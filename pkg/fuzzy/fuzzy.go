@@ -0,0 +1,42 @@
+// Package fuzzy contains the approximate string matching used to find tasks by title.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Match reports whether every rune of pattern appears in s in order, ignoring case, and scores
+// the match. Higher scores are better: runs of consecutive runes and runes at the start of a
+// word score extra.
+func Match(pattern string, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	score := 0
+	pi := 0
+	lastMatch := -2
+	for i := 0; i < len(r) && pi < len(p); i++ {
+		if r[i] != p[pi] {
+			continue
+		}
+
+		score++
+		if lastMatch == i-1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(r[i-1]) && !unicode.IsDigit(r[i-1]) {
+			score += 3
+		}
+		lastMatch = i
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
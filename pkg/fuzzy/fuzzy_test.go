@@ -0,0 +1,26 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	_, ok := Match("rvw", "Review MR")
+	assert.True(t, ok)
+
+	_, ok = Match("mrr", "Review MR")
+	assert.False(t, ok)
+
+	_, ok = Match("", "anything")
+	assert.True(t, ok)
+
+	contiguous, _ := Match("review", "Review MR")
+	scattered, _ := Match("review", "Rewrite the overview")
+	assert.Greater(t, contiguous, scattered)
+
+	wordStart, _ := Match("mr", "Review MR")
+	midWord, _ := Match("mr", "summer")
+	assert.Greater(t, wordStart, midWord)
+}
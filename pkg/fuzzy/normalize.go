@@ -0,0 +1,95 @@
+package fuzzy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Rule is a normalisation applied to titles before they are compared.
+type Rule string
+
+const (
+	// CaseRule ignores upper and lower case.
+	CaseRule Rule = "case"
+	// SpaceRule trims and collapses runs of whitespace.
+	SpaceRule Rule = "space"
+	// EmojiRule removes emoji and other pictographic symbols.
+	EmojiRule Rule = "emoji"
+	// MRNumberRule removes a trailing merge request or issue reference such as !123, #45 or (!123).
+	MRNumberRule Rule = "mr-number"
+	// PunctuationRule treats punctuation as whitespace.
+	PunctuationRule Rule = "punctuation"
+)
+
+// Rules lists every rule in the order Normalize applies them.
+var Rules = []Rule{EmojiRule, MRNumberRule, PunctuationRule, CaseRule, SpaceRule}
+
+// DefaultRules only ignore differences that never change what a title means.
+var DefaultRules = []Rule{CaseRule, SpaceRule, EmojiRule}
+
+var mrNumberPattern = regexp.MustCompile(`\s*[(\[]?[!#]\d+[)\]]?\s*$`)
+
+func ParseRule(s string) (Rule, error) {
+	for _, rule := range Rules {
+		if strings.EqualFold(strings.TrimSpace(s), string(rule)) {
+			return rule, nil
+		}
+	}
+
+	var names []string
+	for _, rule := range Rules {
+		names = append(names, string(rule))
+	}
+	return "", fmt.Errorf("unknown normalisation rule %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// Normalize applies rules to s. The order rules are given in does not matter.
+func Normalize(s string, rules ...Rule) string {
+	enabled := make(map[Rule]bool)
+	for _, rule := range rules {
+		enabled[rule] = true
+	}
+
+	if enabled[EmojiRule] {
+		s = strings.Map(func(r rune) rune {
+			if isEmoji(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if enabled[MRNumberRule] {
+		s = mrNumberPattern.ReplaceAllString(s, "")
+	}
+	if enabled[PunctuationRule] {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsPunct(r) {
+				return ' '
+			}
+			return r
+		}, s)
+	}
+	if enabled[CaseRule] {
+		s = strings.ToLower(s)
+	}
+	if enabled[SpaceRule] {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	return s
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case unicode.Is(unicode.So, r):
+		return true
+	case r == '\u200d', r >= '\ufe00' && r <= '\ufe0f':
+		// zero width joiners and variation selectors glue emoji together
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// skin tone modifiers
+		return true
+	}
+	return false
+}
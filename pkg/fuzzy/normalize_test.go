@@ -0,0 +1,42 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in    string
+		rules []Rule
+		want  string
+	}{
+		{"  Review   MR  ", DefaultRules, "review mr"},
+		{"Review MR 🚀", DefaultRules, "review mr"},
+		{"Deploy 👍🏽 now", DefaultRules, "deploy now"},
+		{"Party 👨‍👩‍👧 time ❤️", DefaultRules, "party time"},
+		{"Review MR !123", DefaultRules, "review mr !123"},
+		{"Review MR !123", []Rule{MRNumberRule}, "Review MR"},
+		{"Review MR (#45)", []Rule{MRNumberRule}, "Review MR"},
+		{"Review MR [!45] 🚀", []Rule{EmojiRule, MRNumberRule, SpaceRule}, "Review MR"},
+		{"Issue #12 follow up", []Rule{MRNumberRule}, "Issue #12 follow up"},
+		{"fix: the-bug.", []Rule{PunctuationRule, SpaceRule}, "fix the bug"},
+		{"Unchanged  Title", nil, "Unchanged  Title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.in, tt.rules...))
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule(" MR-Number ")
+	require.NoError(t, err)
+	assert.Equal(t, MRNumberRule, rule)
+
+	_, err = ParseRule("stemming")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package fuzzy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Algorithm scores how similar two strings are between 0 (nothing in common) and 1 (equal).
+type Algorithm string
+
+const (
+	// LevenshteinAlgorithm compares strings rune by rune, see Similarity.
+	LevenshteinAlgorithm Algorithm = "levenshtein"
+	// TokenSetAlgorithm compares the sets of words in the strings, see TokenSetSimilarity.
+	TokenSetAlgorithm Algorithm = "token-set"
+)
+
+// Algorithms lists every supported algorithm.
+var Algorithms = []Algorithm{LevenshteinAlgorithm, TokenSetAlgorithm}
+
+func ParseAlgorithm(s string) (Algorithm, error) {
+	for _, algorithm := range Algorithms {
+		if strings.EqualFold(s, string(algorithm)) {
+			return algorithm, nil
+		}
+	}
+	return "", fmt.Errorf("unknown similarity algorithm %q, expected %s or %s", s, LevenshteinAlgorithm, TokenSetAlgorithm)
+}
+
+// Similarity scores a and b with the algorithm.
+func (a Algorithm) Similarity(s, t string) float64 {
+	if a == TokenSetAlgorithm {
+		return TokenSetSimilarity(s, t)
+	}
+	return Similarity(s, t)
+}
+
+// Levenshtein returns the number of single rune insertions, deletions and substitutions needed
+// to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity is the Levenshtein distance between a and b scaled to between 0 and 1 by the length
+// of the longer string.
+func Similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// TokenSetSimilarity is the number of words a and b have in common divided by the number of
+// distinct words in either, so word order and repeated words do not matter.
+func TokenSetSimilarity(a, b string) float64 {
+	ta, tb := tokenSet(a), tokenSet(b)
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+
+	common := 0
+	for token := range ta {
+		if tb[token] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+func tokenSet(s string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range strings.Fields(s) {
+		tokens[token] = true
+	}
+	return tokens
+}
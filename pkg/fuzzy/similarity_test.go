@@ -0,0 +1,51 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"review MR", "review MR", 0},
+		{"review MR", "Review MR", 1},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Levenshtein(tt.a, tt.b), "%q %q", tt.a, tt.b)
+		assert.Equal(t, tt.want, Levenshtein(tt.b, tt.a), "%q %q", tt.b, tt.a)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 1.0, Similarity("same", "same"))
+	assert.Equal(t, 0.0, Similarity("abc", "xyz"))
+	assert.InDelta(t, 0.9, Similarity("review MR1", "review MR2"), 0.001)
+}
+
+func TestTokenSetSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TokenSetSimilarity("", ""))
+	assert.Equal(t, 1.0, TokenSetSimilarity("fix the bug", "bug fix the the"))
+	assert.Equal(t, 0.6, TokenSetSimilarity("fix login bug", "fix login crash bug extra"))
+	assert.Equal(t, 0.0, TokenSetSimilarity("one", "two"))
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	algorithm, err := ParseAlgorithm("Token-Set")
+	require.NoError(t, err)
+	assert.Equal(t, TokenSetAlgorithm, algorithm)
+	assert.Equal(t, 1.0, algorithm.Similarity("a b", "b a"))
+
+	_, err = ParseAlgorithm("soundex")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package input
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -14,6 +15,9 @@ import (
 
 var tries = 0
 
+// ErrNotInteractive is returned instead of prompting when stdin is not a terminal, e.g. in CI or cron.
+var ErrNotInteractive = errors.New("cannot prompt for input as stdin is not a terminal, pass the value as a flag instead")
+
 type Config struct {
 	required  bool
 	secure    bool
@@ -24,6 +28,11 @@ func isTerminal() bool {
 	return terminal.IsTerminal(int(os.Stdout.Fd()))
 }
 
+// IsInteractive reports whether the user can be prompted for input.
+func IsInteractive() bool {
+	return terminal.IsTerminal(int(os.Stdin.Fd()))
+}
+
 func getAskOptions(options *survey.AskOptions) (err error) {
 	// use stdout if not piping
 	if isTerminal() {
@@ -44,6 +53,10 @@ func getAskOptions(options *survey.AskOptions) (err error) {
 }
 
 func AskString(question string) string {
+	if !IsInteractive() {
+		logrus.Fatal(ErrNotInteractive)
+	}
+
 	response := ""
 	var prompt survey.Prompt
 	prompt = &survey.Input{Message: fmt.Sprintf("%s?", question)}
@@ -56,6 +69,10 @@ func AskString(question string) string {
 }
 
 func AskStringWithOptions(question, def string, options Config) (string, error) {
+	if !IsInteractive() {
+		return "", ErrNotInteractive
+	}
+
 	tries++
 
 	message := fmt.Sprintf("%s: ", question)
@@ -110,6 +127,10 @@ func AskMultiSelect(question string, options []string) []string {
 }
 
 func AskMultiSelectWithError(question string, options []string) ([]string, error) {
+	if !IsInteractive() {
+		return nil, ErrNotInteractive
+	}
+
 	response := []string{}
 
 	prompt := &survey.MultiSelect{
@@ -154,6 +175,10 @@ func AskSelectWithFilter(question string, options []string, filter string) strin
 }
 
 func AskSelectWithError(question string, options []string) (string, error) {
+	if !IsInteractive() {
+		return "", ErrNotInteractive
+	}
+
 	response := ""
 	prompt := &survey.Select{
 		Message:  fmt.Sprintf("%s:", question),
@@ -175,6 +200,10 @@ func AskForConfirmation(question string) bool {
 }
 
 func AskForConfirmationWithError(question string) (bool, error) {
+	if !IsInteractive() {
+		return false, ErrNotInteractive
+	}
+
 	response := false
 	prompt := &survey.Confirm{
 		Message: question,
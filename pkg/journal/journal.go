@@ -0,0 +1,182 @@
+// Package journal records dedupe runs on disk before they change any task so that a run that
+// stopped part way through can be resumed or rolled back.
+package journal
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/petetanton/reclaim-cli/pkg/jsondir"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+// Group is one set of duplicate tasks: the survivor as it was before the run, the patch that
+// merges the duplicates into it and the duplicates that are deleted once it has been applied.
+type Group struct {
+	Title      string            `json:"title"`
+	Survivor   *reclaim.Task     `json:"survivor"`
+	Patch      reclaim.TaskPatch `json:"patch"`
+	Duplicates []*reclaim.Task   `json:"duplicates"`
+	// Patched is set once the patch has been applied to the survivor.
+	Patched bool `json:"patched"`
+	// Deleted holds the ids of the duplicates that have been deleted so far.
+	Deleted []int `json:"deleted"`
+}
+
+// IsDeleted reports whether the duplicate with taskId has been deleted.
+func (g *Group) IsDeleted(taskId int) bool {
+	return slices.Contains(g.Deleted, taskId)
+}
+
+// Done reports whether the survivor has been patched and every duplicate deleted.
+func (g *Group) Done() bool {
+	for _, duplicate := range g.Duplicates {
+		if !g.IsDeleted(duplicate.Id) {
+			return false
+		}
+	}
+	return g.Patched
+}
+
+// Revert is the patch that puts back every field of the survivor that Patch changes.
+func (g *Group) Revert() reclaim.TaskPatch {
+	var revert reclaim.TaskPatch
+	if g.Patch.TimeChunksRemaining != nil {
+		revert.TimeChunksRemaining = reclaim.Ptr(g.Survivor.TimeChunksRemaining)
+	}
+	if g.Patch.TimeChunksRequired != nil {
+		revert.TimeChunksRequired = reclaim.Ptr(g.Survivor.TimeChunksRequired)
+	}
+	if g.Patch.Notes != nil {
+		revert.Notes = reclaim.Ptr(g.Survivor.Notes)
+	}
+	if g.Patch.Due != nil {
+		revert.Due = reclaim.Ptr(g.Survivor.Due)
+	}
+	return revert
+}
+
+// Changed returns the names of the fields of current, the survivor as it is now, that are not what
+// the journal expects: their values from before the run until Patch has been applied, and the
+// patched values after. Fields that Patch does not change are not compared.
+func (g *Group) Changed(current *reclaim.Task) []string {
+	expected := g.Patch
+	if !g.Patched {
+		expected = g.Revert()
+	}
+
+	var changed []string
+	if expected.TimeChunksRemaining != nil && *expected.TimeChunksRemaining != current.TimeChunksRemaining {
+		changed = append(changed, "timeChunksRemaining")
+	}
+	if expected.TimeChunksRequired != nil && *expected.TimeChunksRequired != current.TimeChunksRequired {
+		changed = append(changed, "timeChunksRequired")
+	}
+	if expected.Notes != nil && *expected.Notes != current.Notes {
+		changed = append(changed, "notes")
+	}
+	if expected.Due != nil && (expected.Due.Valid != current.Due.Valid || !expected.Due.Time.Equal(current.Due.Time)) {
+		changed = append(changed, "due")
+	}
+	return changed
+}
+
+// Run is every group of one dedupe run.
+type Run struct {
+	// Id is where the run is kept in the journal directory.
+	Id      string    `json:"-"`
+	Started time.Time `json:"started"`
+	Groups  []*Group  `json:"groups"`
+}
+
+// Journal is a directory of runs, one JSON file per run that has not finished. It is safe to use
+// from several goroutines.
+type Journal struct {
+	mu    sync.Mutex
+	files *jsondir.Dir
+}
+
+func New(dir string) *Journal {
+	return &Journal{files: jsondir.New(dir)}
+}
+
+// DefaultDir is the journal directory used when none is configured.
+func DefaultDir() (string, error) {
+	return jsondir.ConfigDir("journal")
+}
+
+func (j *Journal) Dir() string {
+	return j.files.Path()
+}
+
+// Begin records a run of groups. It must be called before any of the groups are applied.
+func (j *Journal) Begin(groups []*Group) (*Run, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	run := &Run{Started: time.Now(), Groups: groups}
+	run.Id = fmt.Sprintf("dedupe-%d", run.Started.UnixNano())
+	if err := j.files.Write(run.Id, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Update makes change to run and writes it. Call it after each step of a group has been applied so
+// that the journal never records a step that did not happen.
+func (j *Journal) Update(run *Run, change func()) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	change()
+	return j.files.Write(run.Id, run)
+}
+
+// Unfinished returns every run that has not been finished, oldest first.
+func (j *Journal) Unfinished() ([]*Run, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ids, err := j.files.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []*Run
+	for _, id := range ids {
+		run, err := j.read(id)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, k int) bool {
+		return runs[i].Started.Before(runs[k].Started)
+	})
+	return runs, nil
+}
+
+// Finish removes run once every group has been applied or rolled back.
+func (j *Journal) Finish(run *Run) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.files.Remove(run.Id)
+}
+
+func (j *Journal) read(id string) (*Run, error) {
+	run := &Run{Id: id}
+	if err := j.files.Read(id, run); err != nil {
+		return nil, fmt.Errorf("could not read journal %s: %w", id, err)
+	}
+	for _, group := range run.Groups {
+		if group.Survivor == nil {
+			return nil, fmt.Errorf("journal %s has a group without a survivor", id)
+		}
+	}
+	return run, nil
+}
@@ -0,0 +1,102 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+func TestJournal(t *testing.T) {
+	journal := New(filepath.Join(t.TempDir(), "journal"))
+
+	runs, err := journal.Unfinished()
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	group := &Group{
+		Title:      "review MR",
+		Survivor:   &reclaim.Task{Id: 1, Title: "review MR", TimeChunksRemaining: 2},
+		Patch:      reclaim.TaskPatch{TimeChunksRemaining: reclaim.Ptr(reclaim.Chunks(5))},
+		Duplicates: []*reclaim.Task{{Id: 2, Title: "review MR"}, {Id: 3, Title: "review MR"}},
+	}
+	first, err := journal.Begin([]*Group{group})
+	require.NoError(t, err)
+	second, err := journal.Begin([]*Group{{Title: "other", Survivor: &reclaim.Task{Id: 4}}})
+	require.NoError(t, err)
+
+	require.NoError(t, journal.Update(first, func() {
+		group.Patched = true
+		group.Deleted = append(group.Deleted, 2)
+	}))
+
+	runs, err = journal.Unfinished()
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, []string{first.Id, second.Id}, []string{runs[0].Id, runs[1].Id})
+
+	saved := runs[0].Groups[0]
+	assert.True(t, saved.Patched)
+	assert.True(t, saved.IsDeleted(2))
+	assert.False(t, saved.IsDeleted(3))
+	assert.False(t, saved.Done())
+	assert.Equal(t, reclaim.Chunks(5), *saved.Patch.TimeChunksRemaining)
+	assert.Equal(t, "review MR", saved.Duplicates[1].Title)
+
+	saved.Deleted = append(saved.Deleted, 3)
+	assert.True(t, saved.Done())
+
+	require.NoError(t, journal.Finish(first))
+	runs, err = journal.Unfinished()
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, second.Id, runs[0].Id)
+}
+
+func TestGroup_Revert(t *testing.T) {
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	group := &Group{
+		Survivor: &reclaim.Task{Id: 1, Notes: "original", TimeChunksRemaining: 2, TimeChunksRequired: 3, Due: reclaim.NewNullableTime(due)},
+		Patch: reclaim.TaskPatch{
+			TimeChunksRemaining: reclaim.Ptr(reclaim.Chunks(4)),
+			TimeChunksRequired:  reclaim.Ptr(reclaim.Chunks(6)),
+			Notes:               reclaim.Ptr("original\n\nmerged"),
+		},
+	}
+
+	revert := group.Revert()
+	assert.Equal(t, reclaim.Chunks(2), *revert.TimeChunksRemaining)
+	assert.Equal(t, reclaim.Chunks(3), *revert.TimeChunksRequired)
+	assert.Equal(t, "original", *revert.Notes)
+	assert.Nil(t, revert.Due, "the due date was not changed so it is not reverted")
+}
+
+func TestGroup_Changed(t *testing.T) {
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	group := &Group{
+		Survivor: &reclaim.Task{Id: 1, Notes: "original", TimeChunksRemaining: 2, TimeChunksRequired: 3},
+		Patch: reclaim.TaskPatch{
+			TimeChunksRemaining: reclaim.Ptr(reclaim.Chunks(4)),
+			TimeChunksRequired:  reclaim.Ptr(reclaim.Chunks(6)),
+			Due:                 reclaim.Ptr(reclaim.NewNullableTime(due)),
+		},
+	}
+
+	before := *group.Survivor
+	assert.Empty(t, group.Changed(&before))
+	before.Notes = "edited, but notes are not merged"
+	assert.Empty(t, group.Changed(&before))
+	before.TimeChunksRemaining = 1
+	before.Due = reclaim.NewNullableTime(due)
+	assert.Equal(t, []string{"timeChunksRemaining", "due"}, group.Changed(&before))
+
+	group.Patched = true
+	after := reclaim.Task{Id: 1, TimeChunksRemaining: 4, TimeChunksRequired: 6, Due: reclaim.NewNullableTime(due.In(time.Local))}
+	assert.Empty(t, group.Changed(&after))
+	after.TimeChunksRemaining = 3
+	assert.Equal(t, []string{"timeChunksRemaining"}, group.Changed(&after))
+}
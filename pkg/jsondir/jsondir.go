@@ -0,0 +1,100 @@
+// Package jsondir keeps values as JSON files in a directory, one file per id. It backs the local
+// state of the CLI such as the trash and the dedupe journal.
+package jsondir
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dir is a directory of JSON files. It is created, readable only by the user, on the first Write.
+type Dir struct {
+	path string
+}
+
+func New(path string) *Dir {
+	return &Dir{path: path}
+}
+
+// ConfigDir is the directory called name that the CLI keeps under the user's config directory.
+func ConfigDir(name string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "reclaim-cli", name), nil
+}
+
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// Write saves v as id. It is written to a temporary file that is then renamed over id, so readers
+// see either the old or the new file and never a partly written one.
+func (d *Dir) Write(id string, v any) error {
+	if err := os.MkdirAll(d.path, 0o700); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(d.path, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), d.file(id))
+}
+
+// Read decodes the file saved as id into v.
+func (d *Dir) Read(id string, v any) error {
+	b, err := os.ReadFile(d.file(id))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// List returns the id of every file, in name order. A directory that does not exist yet is empty.
+func (d *Dir) List() ([]string, error) {
+	files, err := os.ReadDir(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), ".json")
+		if file.IsDir() || !ok || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Remove deletes the file saved as id.
+func (d *Dir) Remove(id string) error {
+	return os.Remove(d.file(id))
+}
+
+func (d *Dir) file(id string) string {
+	return filepath.Join(d.path, id+".json")
+}
@@ -0,0 +1,44 @@
+package jsondir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	dir := New(filepath.Join(t.TempDir(), "state"))
+
+	ids, err := dir.List()
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	type value struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, dir.Write("b", value{Name: "second"}))
+	require.NoError(t, dir.Write("a", value{Name: "first"}))
+	require.NoError(t, dir.Write("a", value{Name: "first again"}))
+
+	// temporary and unrelated files are skipped
+	require.NoError(t, os.WriteFile(filepath.Join(dir.Path(), ".tmp-123"), []byte("{"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir.Path(), "notes.txt"), []byte("hi"), 0o600))
+
+	ids, err = dir.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	var got value
+	require.NoError(t, dir.Read("a", &got))
+	assert.Equal(t, "first again", got.Name)
+
+	require.NoError(t, dir.Remove("a"))
+	assert.ErrorIs(t, dir.Read("a", &got), os.ErrNotExist)
+
+	info, err := os.Stat(dir.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+}
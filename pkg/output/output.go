@@ -0,0 +1,111 @@
+// Package output renders command results as a table, CSV, JSON or YAML.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	TableFormat Format = "table"
+	JSONFormat  Format = "json"
+	CSVFormat   Format = "csv"
+	YAMLFormat  Format = "yaml"
+)
+
+var Formats = []Format{TableFormat, JSONFormat, CSVFormat, YAMLFormat}
+
+func ParseFormat(s string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(s, string(format)) {
+			return format, nil
+		}
+	}
+
+	var names []string
+	for _, format := range Formats {
+		names = append(names, string(format))
+	}
+	return "", fmt.Errorf("unknown output format %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// Table is a command result. Headers and Rows are used for the table and CSV formats, Values is
+// marshalled as is for JSON and YAML so that no fields are lost.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	Values  interface{}
+}
+
+func Write(w io.Writer, format Format, t Table) error {
+	switch format {
+	case TableFormat:
+		return writeTable(w, t)
+	case CSVFormat:
+		return writeCSV(w, t)
+	case JSONFormat:
+		return writeJSON(w, t.Values)
+	case YAMLFormat:
+		return writeYAML(w, t.Values)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+func writeTable(w io.Writer, t Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, strings.Join(t.Headers, "\t")); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeYAML goes via JSON so that the keys match the API and the JSON output, and so that types
+// with a custom MarshalJSON such as reclaim.NullableTime render the same way.
+func writeYAML(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestWrite(t *testing.T) {
+	table := Table{
+		Headers: []string{"ID", "TITLE"},
+		Rows:    [][]string{{"1", "short"}, {"22", `with "quotes", commas`}},
+		Values:  []row{{Id: 1, Title: "short"}, {Id: 22, Title: `with "quotes", commas`}},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{format: TableFormat, want: "ID  TITLE\n1   short\n22  with \"quotes\", commas\n"},
+		{format: CSVFormat, want: "ID,TITLE\n1,short\n22,\"with \"\"quotes\"\", commas\"\n"},
+		{format: JSONFormat, want: "[\n  {\n    \"id\": 1,\n    \"title\": \"short\"\n  },\n  {\n    \"id\": 22,\n    \"title\": \"with \\\"quotes\\\", commas\"\n  }\n]\n"},
+		{format: YAMLFormat, want: "- id: 1\n  title: short\n- id: 22\n  title: with \"quotes\", commas\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, tt.format, table))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, JSONFormat, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestWriteTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{.Id}} {{upper .Title}} {{json .}}`)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTemplate(&buf, tmpl, row{Id: 1, Title: "a"}, &row{Id: 2, Title: "b"}))
+	assert.Equal(t, "1 A {\"id\":1,\"title\":\"a\"}\n2 B {\"id\":2,\"title\":\"b\"}\n", buf.String())
+
+	_, err = ParseTemplate(`{{.Id`)
+	assert.Error(t, err)
+
+	tmpl, err = ParseTemplate(`{{.Missing}}`)
+	require.NoError(t, err)
+	assert.Error(t, WriteTemplate(&buf, tmpl, row{}))
+}
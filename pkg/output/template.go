@@ -0,0 +1,45 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join": func(sep string, values []string) string {
+		return strings.Join(values, sep)
+	},
+}
+
+// ParseTemplate parses a --format template such as '{{.Id}} {{.Title}}'. As well as the
+// text/template builtins it provides json, upper, lower and join.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WriteTemplate executes tmpl once for every value, each on its own line.
+func WriteTemplate(w io.Writer, tmpl *template.Template, values ...interface{}) error {
+	for _, v := range values {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, v); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
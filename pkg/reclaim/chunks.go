@@ -0,0 +1,66 @@
+package reclaim
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChunkSize is the granularity Reclaim schedules tasks in.
+const ChunkSize = time.Minute * 15
+
+// Chunks is an amount of time measured in ChunkSize blocks, as used for a task's required time
+// and its min and max chunk sizes.
+type Chunks int
+
+// ChunksFromDuration rounds d to the nearest whole chunk, never returning less than one chunk for
+// a positive duration. rounded reports whether d was not already a whole number of chunks.
+func ChunksFromDuration(d time.Duration) (chunks Chunks, rounded bool) {
+	if d <= 0 {
+		return 0, d != 0
+	}
+
+	chunks = Chunks(math.Round(float64(d) / float64(ChunkSize)))
+	if chunks < 1 {
+		chunks = 1
+	}
+	return chunks, chunks.Duration() != d
+}
+
+// ParseChunks parses a duration such as 1h30m, 90m or 2.5h, or a plain number of minutes, and
+// rounds it to the nearest chunk. rounded reports whether rounding changed the value.
+func ParseChunks(s string) (chunks Chunks, rounded bool, err error) {
+	s = strings.TrimSpace(s)
+
+	var d time.Duration
+	if minutes, err := strconv.ParseFloat(s, 64); err == nil {
+		d = time.Duration(minutes * float64(time.Minute))
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, false, fmt.Errorf("could not parse %q as a duration, expected e.g. 45m, 1h30m or 2.5h", s)
+	}
+
+	if d <= 0 {
+		return 0, false, fmt.Errorf("duration %q must be positive", s)
+	}
+
+	chunks, rounded = ChunksFromDuration(d)
+	return chunks, rounded, nil
+}
+
+func (c Chunks) Duration() time.Duration {
+	return time.Duration(c) * ChunkSize
+}
+
+// String formats the chunks as a duration such as 45m, 2h or 1h30m.
+func (c Chunks) String() string {
+	minutes := int(c.Duration() / time.Minute)
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
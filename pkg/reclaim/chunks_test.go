@@ -0,0 +1,52 @@
+package reclaim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChunks(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Chunks
+		rounded bool
+		wantErr bool
+	}{
+		{in: "15m", want: 1},
+		{in: "90m", want: 6},
+		{in: "90", want: 6},
+		{in: "1h30m", want: 6},
+		{in: "2.5h", want: 10},
+		{in: "20m", want: 1, rounded: true},
+		{in: "25m", want: 2, rounded: true},
+		{in: "5m", want: 1, rounded: true},
+		{in: "1h", want: 4},
+		{in: "0", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "a while", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, rounded, err := ParseChunks(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.rounded, rounded)
+		})
+	}
+}
+
+func TestChunks_String(t *testing.T) {
+	assert.Equal(t, "0m", Chunks(0).String())
+	assert.Equal(t, "45m", Chunks(3).String())
+	assert.Equal(t, "2h", Chunks(8).String())
+	assert.Equal(t, "1h30m", Chunks(6).String())
+	assert.Equal(t, time.Minute*90, Chunks(6).Duration())
+}
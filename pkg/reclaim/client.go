@@ -1,7 +1,10 @@
 package reclaim
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,10 +13,11 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 )
 
 const (
-	apiUrl = "https://api.app.reclaim.ai"
+	DefaultBaseURL = "https://api.app.reclaim.ai"
 )
 
 type TaskPriority string
@@ -25,225 +29,267 @@ const (
 	P4 TaskPriority = "P4"
 )
 
+type EventCategory string
+
+const (
+	Work     EventCategory = "WORK"
+	Personal EventCategory = "PERSONAL"
+)
+
 type Client struct {
-	h      http.Client
-	apiKey string
+	h           http.Client
+	apiKey      string
+	baseURL     string
+	retryPolicy RetryPolicy
+	limiter     *rate.Limiter
+}
+
+// Option configures a Client created with New.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different Reclaim API, e.g. a reclaimtest.Server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
 }
 
-func New() *Client {
-	return &Client{h: http.Client{
+// WithAPIKey overrides the API key read from RECLAIM_API_KEY.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// New creates a client configured from RECLAIM_API_KEY and RECLAIM_API_URL, then applies opts.
+func New(opts ...Option) *Client {
+	c := &Client{h: http.Client{
 		Timeout: time.Second * 10,
 	},
-		apiKey: os.Getenv("RECLAIM_API_KEY")}
+		apiKey:      os.Getenv("RECLAIM_API_KEY"),
+		baseURL:     DefaultBaseURL,
+		retryPolicy: DefaultRetryPolicy,
+		limiter:     newLimiter(DefaultRateLimit, DefaultRateBurst),
+	}
+	if baseURL := os.Getenv("RECLAIM_API_URL"); baseURL != "" {
+		WithBaseURL(baseURL)(c)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) do(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
-	return c.h.Do(req)
+	for attempt := 0; ; attempt++ {
+		// retries count against the limit too so that a struggling API is not hammered
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		response, err := c.h.Do(req)
+		if attempt >= c.retryPolicy.MaxRetries || !shouldRetry(req, response, err) {
+			return response, err
+		}
+
+		wait := c.retryPolicy.backoff(attempt, response)
+		if err != nil {
+			logrus.Debugf("%s %s failed, retrying in %s: %v", req.Method, req.URL.Path, wait, err)
+		} else {
+			logrus.Debugf("%s %s returned %d, retrying in %s", req.Method, req.URL.Path, response.StatusCode, wait)
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
 }
 
-func (c *Client) CreateTask(title string, minChunkSize int, maxChunkSize int, timeChunksRequired int, priority TaskPriority) (*Task, error) {
-	requestBody := fmt.Sprintf(`{
-		"title": "%s",
-		"status": "NEW",
-		"minChunkSize": %d,
-		"maxChunkSize": %d,
-		"timeChunksRequired": %d,
-		"eventCategory": "WORK",
-		"priority": "%s"
-}`, title, minChunkSize, maxChunkSize, timeChunksRequired, priority)
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/tasks", apiUrl), strings.NewReader(requestBody))
-
+// request sends body to path and decodes the response into out, which may be nil.
+// Non 2xx responses are returned as an *APIError.
+func (c *Client) request(ctx context.Context, method string, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", c.baseURL, path), body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	response, err := c.do(request)
+	response, err := c.do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
 	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d when creating a task:\n%s", response.StatusCode, requestBody)
-	}
 
-	var task *Task
-	reqBytes, err := io.ReadAll(response.Body)
+	responseBytes, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	err = json.Unmarshal(reqBytes, &task)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return newAPIError(response, responseBytes)
+	}
 
-	if err != nil {
-		return nil, err
+	if out == nil || len(responseBytes) == 0 {
+		return nil
 	}
 
-	return task, nil
+	return json.Unmarshal(responseBytes, out)
 }
 
-func (c *Client) SnoozeTask(taskId int, snoozeUntil time.Time) error {
-	requestBody := fmt.Sprintf(`{
-		"snoozeUntil": "%s"
-}`, snoozeUntil.Format(time.RFC3339Nano))
-	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/tasks/%d", apiUrl, taskId), strings.NewReader(requestBody))
+// CreateTask creates a task, recording opts.ExternalKey in its notes. It does not look for an
+// existing task, use CreateTaskIfNotExists for that.
+func (c *Client) CreateTask(ctx context.Context, opts TaskCreateOptions) (*Task, error) {
+	if strings.TrimSpace(opts.Title) == "" {
+		return nil, errors.New("a task needs a title")
+	}
+	if opts.EventCategory == "" {
+		opts.EventCategory = Work
+	}
+	if opts.ExternalKey != "" {
+		opts.Notes = WithExternalKey(opts.Notes, opts.ExternalKey)
+	}
 
+	requestBody, err := json.Marshal(struct {
+		Status TaskStatus `json:"status"`
+		TaskCreateOptions
+	}{Status: StatusNew, TaskCreateOptions: opts})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	response, err := c.do(request)
+	var task *Task
+	err = c.request(ctx, http.MethodPost, "/api/tasks", bytes.NewReader(requestBody), &task)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code %d when snoozing a task:\n%s", response.StatusCode, requestBody)
+	if task == nil {
+		return nil, fmt.Errorf("create task %s: no task in the response", opts.Title)
 	}
 
-	return nil
+	return task, nil
 }
 
-func (c *Client) GetTasks(statuses []string) ([]*Task, error) {
-	if len(statuses) == 0 {
-		statuses = []string{"NEW", "SCHEDULED", "IN_PROGRESS", "COMPLETE"}
-	}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/tasks?status=%s", apiUrl, strings.Join(statuses, ",")), nil)
+func (c *Client) SnoozeTask(ctx context.Context, taskId int, snoozeUntil time.Time) (*Task, error) {
+	return c.PatchTask(ctx, taskId, TaskPatch{SnoozeUntil: Ptr(NewNullableTime(snoozeUntil))})
+}
+
+// UnsnoozeTask clears the task's snooze so that it can be scheduled straight away.
+func (c *Client) UnsnoozeTask(ctx context.Context, taskId int) (*Task, error) {
+	return c.PatchTask(ctx, taskId, TaskPatch{SnoozeUntil: &NullableTime{}})
+}
+
+// PatchTask sends only the fields set in patch, leaving everything else on the task untouched. When
+// the response has no task in it the patched task is fetched.
+func (c *Client) PatchTask(ctx context.Context, taskId int, patch TaskPatch) (*Task, error) {
+	requestBody, err := json.Marshal(patch)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := c.do(request)
+	var task *Task
+	err = c.request(ctx, http.MethodPatch, fmt.Sprintf("/api/tasks/%d", taskId), bytes.NewReader(requestBody), &task)
 	if err != nil {
 		return nil, err
 	}
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d when getting tasks", response.StatusCode)
-	}
-	var tasks []*Task
-	reqBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
+	if task == nil {
+		return c.GetTask(ctx, taskId)
 	}
 
-	err = json.Unmarshal(reqBytes, &tasks)
+	return task, nil
+}
 
+func (c *Client) GetTask(ctx context.Context, taskId int) (*Task, error) {
+	var task *Task
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("/api/tasks/%d", taskId), nil, &task)
 	if err != nil {
 		return nil, err
 	}
+	if task == nil {
+		return nil, fmt.Errorf("get task %d: no task in the response", taskId)
+	}
 
-	return tasks, nil
+	return task, nil
 }
 
-func (c *Client) DeleteTask(taskId int) error {
-	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/tasks/%d", apiUrl, taskId), nil)
-	if err != nil {
-		return err
+// GetTasks returns the tasks in any of statuses, or in DefaultStatuses when none are given.
+func (c *Client) GetTasks(ctx context.Context, statuses ...TaskStatus) ([]*Task, error) {
+	if len(statuses) == 0 {
+		statuses = DefaultStatuses
 	}
 
-	response, err := c.do(request)
+	var tasks []*Task
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("/api/tasks?status=%s", joinStatuses(statuses)), nil, &tasks)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defer response.Body.Close()
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code %d when deleting a task", response.StatusCode)
+	for _, task := range tasks {
+		if !task.Status.IsKnown() {
+			logrus.Warnf("task %d has a status this version does not understand (%s), it will be treated as closed", task.Id, task.Status)
+		}
 	}
 
-	return nil
+	return tasks, nil
 }
 
-func (c *Client) UpdateTask(task *Task) (*Task, error) {
-	requestBody, err := json.Marshal(task)
-	if err != nil {
-		return nil, err
-	}
-
-	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/tasks/%d", apiUrl, task.Id), strings.NewReader(string(requestBody)))
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) DeleteTask(ctx context.Context, taskId int) error {
+	return c.request(ctx, http.MethodDelete, fmt.Sprintf("/api/tasks/%d", taskId), nil, nil)
+}
 
-	response, err := c.do(request)
+// UpdateTask replaces the whole task, overwriting any changes made since it was read.
+//
+// Deprecated: use PatchTask to only send the fields that changed.
+func (c *Client) UpdateTask(ctx context.Context, task *Task) (*Task, error) {
+	requestBody, err := json.Marshal(task)
 	if err != nil {
 		return nil, err
 	}
 
-	defer response.Body.Close()
-
 	var updatedTask *Task
-	reqBytes, err := io.ReadAll(response.Body)
+	err = c.request(ctx, http.MethodPut, fmt.Sprintf("/api/tasks/%d", task.Id), bytes.NewReader(requestBody), &updatedTask)
 	if err != nil {
 		return nil, err
 	}
-
-	err = json.Unmarshal(reqBytes, &updatedTask)
-
-	if err != nil {
-		return nil, err
+	if updatedTask == nil {
+		return c.GetTask(ctx, task.Id)
 	}
 
 	return updatedTask, nil
 }
 
-func (c *Client) GetNextMeetingTime(linkId string) (*MeetingTime, error) {
+func (c *Client) GetNextMeetingTime(ctx context.Context, linkId string) (*MeetingTime, error) {
 	now := time.Now()
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/scheduling-link/%s/meeting/availability/V2?date=%s&zoneId=Europe/London&conferenceType=ZOOM", apiUrl, linkId, now.Format("2006-01-02")), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := c.do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d when getting next meeting time", response.StatusCode)
-	}
 
 	var mtr *MeetingTimeResponse
-	reqBytes, err := io.ReadAll(response.Body)
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("/api/scheduling-link/%s/meeting/availability/V2?date=%s&zoneId=Europe/London&conferenceType=ZOOM", linkId, now.Format("2006-01-02")), nil, &mtr)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(reqBytes, &mtr)
-	if err != nil {
-		return nil, err
+	if mtr == nil || len(mtr.AvailableTimes.ThirtyMinuteSlots) == 0 {
+		return nil, fmt.Errorf("no meeting times available for scheduling link %s", linkId)
 	}
 
 	return mtr.AvailableTimes.ThirtyMinuteSlots[0], nil
 }
 
-func (c *Client) GetScheduleLinks() ([]*ScheduleLink, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/scheduling-link", apiUrl), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := c.do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d when getting scheduling links", response.StatusCode)
-	}
+func (c *Client) GetScheduleLinks(ctx context.Context) ([]*ScheduleLink, error) {
 	var scheduleLinks []*ScheduleLink
-	reqBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(reqBytes, &scheduleLinks)
+	err := c.request(ctx, http.MethodGet, "/api/scheduling-link", nil, &scheduleLinks)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +297,7 @@ func (c *Client) GetScheduleLinks() ([]*ScheduleLink, error) {
 	return scheduleLinks, nil
 }
 
-func (c *Client) CreateMeeting(inviteeName string, inviteeEmail string, title string, meetingTime *MeetingTime, linkId string) (*MeetingResponse, error) {
+func (c *Client) CreateMeeting(ctx context.Context, inviteeName string, inviteeEmail string, title string, meetingTime *MeetingTime, linkId string) (*MeetingResponse, error) {
 	request := &MeetingRequest{
 		InviteeName: inviteeName,
 		Message:     title,
@@ -272,28 +318,8 @@ func (c *Client) CreateMeeting(inviteeName string, inviteeEmail string, title st
 
 	logrus.Info(string(requestBody))
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/scheduling-link/%s/meeting", apiUrl, linkId), strings.NewReader(string(requestBody)))
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := c.do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-	responseBytes, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d when creating a meeting: %s", response.StatusCode, string(responseBytes))
-	}
-
 	var meetingResponse *MeetingResponse
-	err = json.Unmarshal(responseBytes, &meetingResponse)
+	err = c.request(ctx, http.MethodPost, fmt.Sprintf("/api/scheduling-link/%s/meeting", linkId), bytes.NewReader(requestBody), &meetingResponse)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,260 @@
+package reclaim_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func TestClient_Tasks(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, reclaim.TaskCreateOptions{Title: "write tests", MinChunkSize: 1, MaxChunkSize: 8, TimeChunksRequired: 4, Priority: reclaim.P2})
+	require.NoError(t, err)
+	assert.Equal(t, "write tests", task.Title)
+	assert.Equal(t, "WORK", task.EventCategory)
+	assert.Equal(t, reclaim.Chunks(4), task.TimeChunksRequired)
+	assert.Equal(t, "P2", task.Priority)
+
+	got, err := client.GetTask(ctx, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "write tests", got.Title)
+
+	_, err = client.GetTask(ctx, task.Id+100)
+	assert.True(t, reclaim.IsNotFound(err))
+
+	srv.AddTask(&reclaim.Task{Title: "done already", Status: "COMPLETE"})
+
+	open, err := client.GetTasks(ctx, reclaim.StatusNew)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, task.Id, open[0].Id)
+
+	all, err := client.GetTasks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	task.TimeChunksRequired = 6
+	updated, err := client.UpdateTask(ctx, task)
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.Chunks(6), updated.TimeChunksRequired)
+
+	snoozed, err := client.SnoozeTask(ctx, task.Id, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, snoozed.SnoozeUntil.Valid)
+
+	unsnoozed, err := client.UnsnoozeTask(ctx, task.Id)
+	require.NoError(t, err)
+	assert.False(t, unsnoozed.SnoozeUntil.Valid)
+
+	require.NoError(t, client.DeleteTask(ctx, task.Id))
+	assert.Nil(t, srv.Task(task.Id))
+	assert.Error(t, client.DeleteTask(ctx, task.Id))
+}
+
+func TestClient_Meetings(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+	srv.AddScheduleLink(&reclaim.ScheduleLink{Id: "link-1", Title: "1:1"}, &reclaim.MeetingTime{StartTime: start, EndTime: start.Add(time.Minute * 30)})
+
+	links, err := client.GetScheduleLinks(ctx)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "1:1", links[0].Title)
+
+	meetingTime, err := client.GetNextMeetingTime(ctx, "link-1")
+	require.NoError(t, err)
+	assert.True(t, start.Equal(meetingTime.StartTime))
+
+	meeting, err := client.CreateMeeting(ctx, "Jo", "jo@example.com", "catch up", meetingTime, "link-1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, meeting.ConferenceData.JoinUrl)
+	require.Len(t, srv.Meetings(), 1)
+	assert.Equal(t, "jo@example.com", srv.Meetings()[0].InviteeEmail)
+}
+
+func TestClient_CreateTaskEscapesTitle(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	title := `Draft: "fix" C:\path\to\thing`
+	task, err := client.CreateTask(context.Background(), reclaim.TaskCreateOptions{
+		Title:              title,
+		Notes:              "line one\nline two",
+		EventCategory:      reclaim.Personal,
+		TimeChunksRequired: 2,
+		MinChunkSize:       1,
+		MaxChunkSize:       2,
+		AlwaysPrivate:      true,
+		OnDeck:             true,
+	})
+	require.NoError(t, err)
+
+	stored := srv.Task(task.Id)
+	assert.Equal(t, title, stored.Title)
+	assert.Equal(t, "line one\nline two", stored.Notes)
+	assert.Equal(t, "PERSONAL", stored.EventCategory)
+	assert.True(t, stored.AlwaysPrivate)
+	assert.True(t, stored.OnDeck)
+
+	_, err = client.CreateTask(context.Background(), reclaim.TaskCreateOptions{Title: " "})
+	assert.Error(t, err)
+}
+
+func TestClient_CreateTaskExternalKey(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	key := "https://gitlab.example.com/group/project/-/merge_requests/101"
+	opts := reclaim.TaskCreateOptions{Title: "review MR !101", Notes: "please review", ExternalKey: key, TimeChunksRequired: 2, MinChunkSize: 1, MaxChunkSize: 2}
+	task, created, err := client.CreateTaskIfNotExists(ctx, opts, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "please review\n\nreclaim-cli-key: "+key, srv.Task(task.Id).Notes)
+	assert.Equal(t, []string{key}, task.ExternalKeys())
+
+	// the key matches even once the title changed and the task was finished
+	_, err = client.PatchTask(ctx, task.Id, reclaim.TaskPatch{Title: reclaim.Ptr("review MR !101 again"), Status: reclaim.Ptr(reclaim.StatusComplete)})
+	require.NoError(t, err)
+	again, created, err := client.CreateTaskIfNotExists(ctx, opts, false)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, task.Id, again.Id)
+	assert.Len(t, srv.Tasks(), 1)
+
+	opts.ExternalKey = key + "2"
+	other, created, err := client.CreateTaskIfNotExists(ctx, opts, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, task.Id, other.Id)
+
+	// CreateTask never looks for an existing task
+	_, err = client.CreateTask(ctx, opts)
+	require.NoError(t, err)
+	assert.Len(t, srv.Tasks(), 3)
+	assert.Equal(t, 3, srv.Requests(http.MethodGet, "/api/tasks"), "one lookup per CreateTaskIfNotExists")
+
+	assert.Empty(t, (&reclaim.Task{Notes: "no key here"}).ExternalKeys())
+	assert.Equal(t, "reclaim-cli-key: abc", reclaim.WithExternalKey("", " abc "))
+
+	// a task that dedupe merged others into matches each of their keys
+	merged := srv.AddTask(&reclaim.Task{Title: "review MR", Notes: reclaim.WithExternalKeys("merged", &reclaim.Task{Notes: reclaim.WithExternalKey("", "a")}, &reclaim.Task{Notes: reclaim.WithExternalKey("b", "c")})})
+	assert.Equal(t, "merged\n\nreclaim-cli-key: a\nreclaim-cli-key: c", merged.Notes)
+	assert.Equal(t, []string{"a", "c"}, merged.ExternalKeys())
+	found, err := client.FindExistingTask(ctx, reclaim.TaskCreateOptions{Title: "new title", ExternalKey: "c"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, merged.Id, found.Id)
+}
+
+func TestClient_CreateTaskIfNotExists(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	open := srv.AddTask(&reclaim.Task{Title: "Write report", Status: reclaim.StatusNew})
+	srv.AddTask(&reclaim.Task{Title: "file taxes", Status: reclaim.StatusComplete})
+
+	task, created, err := client.CreateTaskIfNotExists(ctx, reclaim.TaskCreateOptions{Title: " write report"}, true)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, open.Id, task.Id)
+
+	// only open tasks count for the title check
+	task, created, err = client.CreateTaskIfNotExists(ctx, reclaim.TaskCreateOptions{Title: "file taxes"}, true)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, open.Id, task.Id)
+	assert.Len(t, srv.Tasks(), 3)
+
+	_, created, err = client.CreateTaskIfNotExists(ctx, reclaim.TaskCreateOptions{Title: "Write report"}, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Len(t, srv.Tasks(), 4)
+}
+
+func TestClient_EmptyResponses(t *testing.T) {
+	getBody := `{"id": 1, "title": "fetched", "status": "NEW"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(getBody))
+		}
+	}))
+	defer srv.Close()
+	client := reclaim.New(reclaim.WithBaseURL(srv.URL), reclaim.WithAPIKey("test"))
+	ctx := context.Background()
+
+	// a patch or update without a task in the response fetches it
+	task, err := client.SnoozeTask(ctx, 1, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "fetched", task.Title)
+	task, err = client.UpdateTask(ctx, &reclaim.Task{Id: 1, Title: "fetched"})
+	require.NoError(t, err)
+	assert.Equal(t, "fetched", task.Title)
+
+	_, err = client.CreateTask(ctx, reclaim.TaskCreateOptions{Title: "new"})
+	assert.ErrorContains(t, err, "no task in the response")
+
+	getBody = ""
+	_, err = client.GetTask(ctx, 1)
+	assert.ErrorContains(t, err, "no task in the response")
+	_, err = client.PatchTask(ctx, 1, reclaim.TaskPatch{Notes: reclaim.Ptr("notes")})
+	assert.Error(t, err)
+}
+
+func TestClient_PatchTask(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	task := srv.AddTask(&reclaim.Task{Title: "original", Notes: "keep me", TimeChunksRequired: 2, TimeChunksRemaining: 2})
+	// simulate a change made in the Reclaim UI after the task was read
+	changed := *task
+	changed.Title = "renamed in the UI"
+	srv.AddTask(&changed)
+
+	patched, err := client.PatchTask(context.Background(), task.Id, reclaim.TaskPatch{
+		TimeChunksRequired: reclaim.Ptr(reclaim.Chunks(4)),
+		Priority:           reclaim.Ptr(reclaim.P1),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed in the UI", patched.Title)
+	assert.Equal(t, "keep me", patched.Notes)
+	assert.Equal(t, reclaim.Chunks(4), patched.TimeChunksRequired)
+	assert.Equal(t, reclaim.Chunks(2), patched.TimeChunksRemaining)
+	assert.Equal(t, "P1", patched.Priority)
+}
+
+func TestClient_PatchTaskDue(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	task, err := client.CreateTask(context.Background(), reclaim.TaskCreateOptions{Title: "due soon", TimeChunksRequired: 1, Due: &due})
+	require.NoError(t, err)
+	assert.True(t, due.Equal(srv.Task(task.Id).Due.Time))
+
+	// an invalid NullableTime clears the date rather than leaving it untouched
+	patched, err := client.PatchTask(context.Background(), task.Id, reclaim.TaskPatch{Due: &reclaim.NullableTime{}})
+	require.NoError(t, err)
+	assert.False(t, patched.Due.Valid)
+}
@@ -0,0 +1,92 @@
+package reclaim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned by every Client method when Reclaim responds with a non 2xx status code.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the human readable message decoded from the error payload, if there was one.
+	Message string
+	// Body is the raw response body.
+	Body      []byte
+	RequestId string
+}
+
+type errorPayload struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+	Title   string `json:"title"`
+	Detail  string `json:"detail"`
+}
+
+var requestIdHeaders = []string{"X-Request-Id", "X-Amzn-RequestId", "X-Amzn-Trace-Id"}
+
+func newAPIError(response *http.Response, body []byte) *APIError {
+	e := &APIError{
+		Method:     response.Request.Method,
+		Path:       response.Request.URL.Path,
+		StatusCode: response.StatusCode,
+		Body:       body,
+	}
+
+	for _, header := range requestIdHeaders {
+		if id := response.Header.Get(header); id != "" {
+			e.RequestId = id
+			break
+		}
+	}
+
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) == nil {
+		for _, msg := range []string{payload.Message, payload.Detail, payload.Error, payload.Title} {
+			if msg != "" {
+				e.Message = msg
+				break
+			}
+		}
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: unexpected status code %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if e.RequestId != "" {
+		msg = fmt.Sprintf("%s (request id %s)", msg, e.RequestId)
+	}
+	return msg
+}
+
+// IsStatus reports whether err is an APIError with the given status code.
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource.
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an APIError caused by Reclaim's rate limits.
+func IsRateLimited(err error) bool {
+	return IsStatus(err, http.StatusTooManyRequests)
+}
+
+// IsUnauthorized reports whether err is an APIError caused by a missing or invalid API key.
+func IsUnauthorized(err error) bool {
+	return IsStatus(err, http.StatusUnauthorized) || IsStatus(err, http.StatusForbidden)
+}
@@ -0,0 +1,69 @@
+package reclaim_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func TestAPIError_NotFound(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	err := srv.Client().DeleteTask(context.Background(), 42)
+	require.Error(t, err)
+
+	var apiErr *reclaim.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.MethodDelete, apiErr.Method)
+	assert.Equal(t, "/api/tasks/42", apiErr.Path)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "task 42 not found", apiErr.Message)
+	assert.NotEmpty(t, apiErr.RequestId)
+	assert.True(t, reclaim.IsNotFound(err))
+	assert.False(t, reclaim.IsRateLimited(err))
+}
+
+func TestAPIError_Payloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+		check   func(error) bool
+	}{
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"error":"slow down"}`, message: "slow down", check: reclaim.IsRateLimited},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"title":"Unauthorized"}`, message: "Unauthorized", check: reclaim.IsUnauthorized},
+		{name: "plain text", status: http.StatusBadGateway, body: "bad gateway\n", message: "bad gateway", check: func(err error) bool {
+			return reclaim.IsStatus(err, http.StatusBadGateway)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			// UpdateTask used to try and decode error bodies as a Task
+			_, err := reclaim.New(reclaim.WithBaseURL(srv.URL), reclaim.WithRetryPolicy(reclaim.RetryPolicy{})).UpdateTask(context.Background(), &reclaim.Task{Id: 1})
+
+			var apiErr *reclaim.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.message, apiErr.Message)
+			assert.Equal(t, []byte(tt.body), apiErr.Body)
+			assert.True(t, tt.check(err))
+		})
+	}
+}
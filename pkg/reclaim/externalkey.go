@@ -0,0 +1,96 @@
+package reclaim
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// externalKeyPrefix starts each line of a task's notes that holds one of its external keys. Reclaim
+// has no field for them, so the keys are kept in the notes where automation can find them again. A
+// task has more than one key once dedupe has merged tasks created from different keys into it.
+const externalKeyPrefix = "reclaim-cli-key:"
+
+// ExternalKeys returns every external key recorded in the task's notes.
+func (t *Task) ExternalKeys() []string {
+	var keys []string
+	for _, line := range strings.Split(t.Notes, "\n") {
+		if key, ok := strings.CutPrefix(strings.TrimSpace(line), externalKeyPrefix); ok {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
+
+// WithExternalKey returns notes with key recorded on a line of its own after them. Key lines are
+// kept together at the end of the notes.
+func WithExternalKey(notes string, key string) string {
+	marker := externalKeyPrefix + " " + strings.TrimSpace(key)
+	notes = strings.TrimRight(notes, "\n")
+	if strings.TrimSpace(notes) == "" {
+		return marker
+	}
+	lines := strings.Split(notes, "\n")
+	if strings.HasPrefix(strings.TrimSpace(lines[len(lines)-1]), externalKeyPrefix) {
+		return notes + "\n" + marker
+	}
+	return notes + "\n\n" + marker
+}
+
+// WithExternalKeys returns notes with every key of tasks that is not in them yet added, so that
+// merging tasks into one keeps all of their keys.
+func WithExternalKeys(notes string, tasks ...*Task) string {
+	for _, task := range tasks {
+		for _, key := range task.ExternalKeys() {
+			if !slices.Contains((&Task{Notes: notes}).ExternalKeys(), key) {
+				notes = WithExternalKey(notes, key)
+			}
+		}
+	}
+	return notes
+}
+
+// FindExistingTask returns the task that CreateTaskIfNotExists would return instead of creating
+// opts, or nil when there is none. A task with opts.ExternalKey matches whatever its status, so
+// finished tasks are not created again. With sameTitle an open task with the same title, ignoring
+// case, matches too. Without either nothing is fetched, otherwise every task is, archived ones
+// included.
+func (c *Client) FindExistingTask(ctx context.Context, opts TaskCreateOptions, sameTitle bool) (*Task, error) {
+	key := strings.TrimSpace(opts.ExternalKey)
+	if key == "" && !sameTitle {
+		return nil, nil
+	}
+
+	tasks, err := c.GetTasks(ctx, KnownStatuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		for _, task := range tasks {
+			if slices.Contains(task.ExternalKeys(), key) {
+				return task, nil
+			}
+		}
+	}
+	if sameTitle {
+		title := strings.TrimSpace(opts.Title)
+		for _, task := range tasks {
+			if task.Status.IsOpen() && strings.EqualFold(strings.TrimSpace(task.Title), title) {
+				return task, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// CreateTaskIfNotExists returns the task FindExistingTask finds for opts and sameTitle, or creates
+// one when there is none. created reports which happened.
+func (c *Client) CreateTaskIfNotExists(ctx context.Context, opts TaskCreateOptions, sameTitle bool) (task *Task, created bool, err error) {
+	existing, err := c.FindExistingTask(ctx, opts, sameTitle)
+	if err != nil || existing != nil {
+		return existing, false, err
+	}
+	task, err = c.CreateTask(ctx, opts)
+	return task, err == nil, err
+}
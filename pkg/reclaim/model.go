@@ -3,31 +3,33 @@ package reclaim
 import "time"
 
 type Task struct {
-	Id                  int     `json:"id"`
-	Title               string  `json:"title"`
-	Notes               string  `json:"notes"`
-	EventCategory       string  `json:"eventCategory"`
-	EventSubType        string  `json:"eventSubType"`
-	Status              string  `json:"status"`
-	TimeChunksRequired  int     `json:"timeChunksRequired"`
-	TimeChunksSpent     int     `json:"timeChunksSpent"`
-	TimeChunksRemaining int     `json:"timeChunksRemaining"`
-	MinChunkSize        int     `json:"minChunkSize"`
-	MaxChunkSize        int     `json:"maxChunkSize"`
-	AlwaysPrivate       bool    `json:"alwaysPrivate"`
-	Deleted             bool    `json:"deleted"`
-	Index               float64 `json:"index"`
-	//Due                 time.Time `json:"due"`  // this field is a strange format e.g. 0000-12-31T23:58:45-00:01:15
-	Created      time.Time `json:"created"`
-	Updated      time.Time `json:"updated"`
-	Finished     time.Time `json:"finished"`
-	Adjusted     bool      `json:"adjusted"`
-	AtRisk       bool      `json:"atRisk"`
-	TimeSchemeId string    `json:"timeSchemeId"`
-	Priority     string    `json:"priority"`
-	OnDeck       bool      `json:"onDeck"`
-	Deferred     bool      `json:"deferred"`
-	SortKey      float64   `json:"sortKey"`
+	Id                  int        `json:"id"`
+	Title               string     `json:"title"`
+	Notes               string     `json:"notes"`
+	EventCategory       string     `json:"eventCategory"`
+	EventSubType        string     `json:"eventSubType"`
+	Status              TaskStatus `json:"status"`
+	TimeChunksRequired  Chunks     `json:"timeChunksRequired"`
+	TimeChunksSpent     Chunks     `json:"timeChunksSpent"`
+	TimeChunksRemaining Chunks     `json:"timeChunksRemaining"`
+	MinChunkSize        Chunks     `json:"minChunkSize"`
+	MaxChunkSize        Chunks     `json:"maxChunkSize"`
+	AlwaysPrivate       bool       `json:"alwaysPrivate"`
+	Deleted             bool       `json:"deleted"`
+	Index               float64    `json:"index"`
+	// Due and SnoozeUntil use NullableTime as Reclaim sends unset dates in a strange format e.g. 0000-12-31T23:58:45-00:01:15
+	Due          NullableTime `json:"due"`
+	SnoozeUntil  NullableTime `json:"snoozeUntil"`
+	Created      time.Time    `json:"created"`
+	Updated      time.Time    `json:"updated"`
+	Finished     time.Time    `json:"finished"`
+	Adjusted     bool         `json:"adjusted"`
+	AtRisk       bool         `json:"atRisk"`
+	TimeSchemeId string       `json:"timeSchemeId"`
+	Priority     string       `json:"priority"`
+	OnDeck       bool         `json:"onDeck"`
+	Deferred     bool         `json:"deferred"`
+	SortKey      float64      `json:"sortKey"`
 	TaskSource   struct {
 		Type string `json:"type"`
 	} `json:"taskSource"`
@@ -36,6 +38,78 @@ type Task struct {
 	Type                    string        `json:"type"`
 }
 
+// TaskCreateOptions is the request body used by Client.CreateTask.
+type TaskCreateOptions struct {
+	Title              string        `json:"title"`
+	Notes              string        `json:"notes,omitempty"`
+	Priority           TaskPriority  `json:"priority,omitempty"`
+	EventCategory      EventCategory `json:"eventCategory"`
+	TimeSchemeId       string        `json:"timeSchemeId,omitempty"`
+	TimeChunksRequired Chunks        `json:"timeChunksRequired"`
+	MinChunkSize       Chunks        `json:"minChunkSize"`
+	MaxChunkSize       Chunks        `json:"maxChunkSize"`
+	Due                *time.Time    `json:"due,omitempty"`
+	SnoozeUntil        *time.Time    `json:"snoozeUntil,omitempty"`
+	AlwaysPrivate      bool          `json:"alwaysPrivate"`
+	OnDeck             bool          `json:"onDeck"`
+
+	// ExternalKey identifies the task in the system it was created from, e.g. a GitLab MR URL. It is
+	// recorded in the notes, and CreateTaskIfNotExists returns an existing task with the same key
+	// instead of creating a new one.
+	ExternalKey string `json:"-"`
+}
+
+// CreateOptions returns the options to create a copy of the task. Only the time still remaining
+// is required of the copy, and a snooze that has already passed is dropped.
+func (t *Task) CreateOptions() TaskCreateOptions {
+	opts := TaskCreateOptions{
+		Title:              t.Title,
+		Notes:              t.Notes,
+		Priority:           TaskPriority(t.Priority),
+		EventCategory:      EventCategory(t.EventCategory),
+		TimeSchemeId:       t.TimeSchemeId,
+		TimeChunksRequired: t.TimeChunksRemaining,
+		MinChunkSize:       t.MinChunkSize,
+		MaxChunkSize:       t.MaxChunkSize,
+		AlwaysPrivate:      t.AlwaysPrivate,
+		OnDeck:             t.OnDeck,
+	}
+	if opts.TimeChunksRequired <= 0 {
+		opts.TimeChunksRequired = t.TimeChunksRequired
+	}
+	if t.Due.Valid {
+		opts.Due = Ptr(t.Due.Time)
+	}
+	if t.SnoozeUntil.After(time.Now()) {
+		opts.SnoozeUntil = Ptr(t.SnoozeUntil.Time)
+	}
+	return opts
+}
+
+// TaskPatch is the request body used by Client.PatchTask. Only non nil fields are sent, so a pointer
+// to an invalid NullableTime clears a date.
+type TaskPatch struct {
+	Title               *string        `json:"title,omitempty"`
+	Notes               *string        `json:"notes,omitempty"`
+	Status              *TaskStatus    `json:"status,omitempty"`
+	Priority            *TaskPriority  `json:"priority,omitempty"`
+	EventCategory       *EventCategory `json:"eventCategory,omitempty"`
+	TimeSchemeId        *string        `json:"timeSchemeId,omitempty"`
+	TimeChunksRequired  *Chunks        `json:"timeChunksRequired,omitempty"`
+	TimeChunksRemaining *Chunks        `json:"timeChunksRemaining,omitempty"`
+	MinChunkSize        *Chunks        `json:"minChunkSize,omitempty"`
+	MaxChunkSize        *Chunks        `json:"maxChunkSize,omitempty"`
+	Due                 *NullableTime  `json:"due,omitempty"`
+	SnoozeUntil         *NullableTime  `json:"snoozeUntil,omitempty"`
+	AlwaysPrivate       *bool          `json:"alwaysPrivate,omitempty"`
+	OnDeck              *bool          `json:"onDeck,omitempty"`
+}
+
+// Ptr returns a pointer to v, which is handy when building a TaskPatch.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 type MeetingResponse struct {
 	MeetingId string `json:"meetingId"`
 	Event     struct {
@@ -152,123 +226,23 @@ type MeetingResponse struct {
 				DisplayName  string `json:"displayName"`
 				Abbreviation string `json:"abbreviation"`
 			} `json:"timezone"`
-			TimePolicyType     string `json:"timePolicyType"`
-			TimeSchemeId       string `json:"timeSchemeId"`
-			ResolvedTimePolicy struct {
-				DayHours struct {
-					FRIDAY struct {
-						Intervals []struct {
-							Start    string  `json:"start"`
-							End      string  `json:"end"`
-							Duration float64 `json:"duration"`
-						} `json:"intervals"`
-						EndOfDay   string `json:"endOfDay"`
-						StartOfDay string `json:"startOfDay"`
-					} `json:"FRIDAY"`
-					MONDAY struct {
-						Intervals []struct {
-							Start    string  `json:"start"`
-							End      string  `json:"end"`
-							Duration float64 `json:"duration"`
-						} `json:"intervals"`
-						EndOfDay   string `json:"endOfDay"`
-						StartOfDay string `json:"startOfDay"`
-					} `json:"MONDAY"`
-					TUESDAY struct {
-						Intervals []struct {
-							Start    string  `json:"start"`
-							End      string  `json:"end"`
-							Duration float64 `json:"duration"`
-						} `json:"intervals"`
-						EndOfDay   string `json:"endOfDay"`
-						StartOfDay string `json:"startOfDay"`
-					} `json:"TUESDAY"`
-					THURSDAY struct {
-						Intervals []struct {
-							Start    string  `json:"start"`
-							End      string  `json:"end"`
-							Duration float64 `json:"duration"`
-						} `json:"intervals"`
-						EndOfDay   string `json:"endOfDay"`
-						StartOfDay string `json:"startOfDay"`
-					} `json:"THURSDAY"`
-					WEDNESDAY struct {
-						Intervals []struct {
-							Start    string  `json:"start"`
-							End      string  `json:"end"`
-							Duration float64 `json:"duration"`
-						} `json:"intervals"`
-						EndOfDay   string `json:"endOfDay"`
-						StartOfDay string `json:"startOfDay"`
-					} `json:"WEDNESDAY"`
-				} `json:"dayHours"`
-				StartOfWeek string `json:"startOfWeek"`
-				EndOfWeek   string `json:"endOfWeek"`
-			} `json:"resolvedTimePolicy"`
-			Status               string   `json:"status"`
-			Optional             bool     `json:"optional"`
-			AttendanceType       string   `json:"attendanceType"`
-			ValidConferenceTypes []string `json:"validConferenceTypes"`
-			TargetCalendarId     int      `json:"targetCalendarId"`
+			TimePolicyType       string     `json:"timePolicyType"`
+			TimeSchemeId         string     `json:"timeSchemeId"`
+			ResolvedTimePolicy   TimePolicy `json:"resolvedTimePolicy"`
+			Status               string     `json:"status"`
+			Optional             bool       `json:"optional"`
+			AttendanceType       string     `json:"attendanceType"`
+			ValidConferenceTypes []string   `json:"validConferenceTypes"`
+			TargetCalendarId     int        `json:"targetCalendarId"`
 		} `json:"organizers"`
-		EffectiveTimePolicy struct {
-			DayHours struct {
-				FRIDAY struct {
-					Intervals []struct {
-						Start    string  `json:"start"`
-						End      string  `json:"end"`
-						Duration float64 `json:"duration"`
-					} `json:"intervals"`
-					EndOfDay   string `json:"endOfDay"`
-					StartOfDay string `json:"startOfDay"`
-				} `json:"FRIDAY"`
-				THURSDAY struct {
-					Intervals []struct {
-						Start    string  `json:"start"`
-						End      string  `json:"end"`
-						Duration float64 `json:"duration"`
-					} `json:"intervals"`
-					EndOfDay   string `json:"endOfDay"`
-					StartOfDay string `json:"startOfDay"`
-				} `json:"THURSDAY"`
-				MONDAY struct {
-					Intervals []struct {
-						Start    string  `json:"start"`
-						End      string  `json:"end"`
-						Duration float64 `json:"duration"`
-					} `json:"intervals"`
-					EndOfDay   string `json:"endOfDay"`
-					StartOfDay string `json:"startOfDay"`
-				} `json:"MONDAY"`
-				TUESDAY struct {
-					Intervals []struct {
-						Start    string  `json:"start"`
-						End      string  `json:"end"`
-						Duration float64 `json:"duration"`
-					} `json:"intervals"`
-					EndOfDay   string `json:"endOfDay"`
-					StartOfDay string `json:"startOfDay"`
-				} `json:"TUESDAY"`
-				WEDNESDAY struct {
-					Intervals []struct {
-						Start    string  `json:"start"`
-						End      string  `json:"end"`
-						Duration float64 `json:"duration"`
-					} `json:"intervals"`
-					EndOfDay   string `json:"endOfDay"`
-					StartOfDay string `json:"startOfDay"`
-				} `json:"WEDNESDAY"`
-			} `json:"dayHours"`
-			StartOfWeek string `json:"startOfWeek"`
-			EndOfWeek   string `json:"endOfWeek"`
-		} `json:"effectiveTimePolicy"`
-		Durations       []int  `json:"durations"`
-		DefaultDuration int    `json:"defaultDuration"`
-		DelayStart      string `json:"delayStart"`
-		DelayStartUnits int    `json:"delayStartUnits"`
-		DaysIntoFuture  int    `json:"daysIntoFuture"`
-		Priority        string `json:"priority"`
-		LocationOptions []struct {
+		EffectiveTimePolicy TimePolicy `json:"effectiveTimePolicy"`
+		Durations           []int      `json:"durations"`
+		DefaultDuration     int        `json:"defaultDuration"`
+		DelayStart          string     `json:"delayStart"`
+		DelayStartUnits     int        `json:"delayStartUnits"`
+		DaysIntoFuture      int        `json:"daysIntoFuture"`
+		Priority            string     `json:"priority"`
+		LocationOptions     []struct {
 			ConferenceType string `json:"conferenceType"`
 		} `json:"locationOptions"`
 		DefaultLocationIndex int           `json:"defaultLocationIndex"`
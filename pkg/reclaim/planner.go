@@ -0,0 +1,80 @@
+package reclaim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PlannerAction is one of the actions Reclaim's planner can take on a task.
+type PlannerAction string
+
+const (
+	ActionDone    PlannerAction = "done"
+	ActionStart   PlannerAction = "start"
+	ActionStop    PlannerAction = "stop"
+	ActionRestart PlannerAction = "restart"
+	ActionLogWork PlannerAction = "log-work"
+)
+
+// PlannerActionResponse is returned by the planner endpoints. TaskOrHabit is the task after the
+// action was applied.
+type PlannerActionResponse struct {
+	TaskOrHabit *Task `json:"taskOrHabit"`
+}
+
+// MarkTaskDone completes the task, removing any time still scheduled for it.
+func (c *Client) MarkTaskDone(ctx context.Context, taskId int) (*Task, error) {
+	return c.plannerAction(ctx, ActionDone, taskId, nil)
+}
+
+// StartTask marks the task as in progress from now.
+func (c *Client) StartTask(ctx context.Context, taskId int) (*Task, error) {
+	return c.plannerAction(ctx, ActionStart, taskId, nil)
+}
+
+// StopTask stops working on an in progress task, leaving the rest of it to be scheduled.
+func (c *Client) StopTask(ctx context.Context, taskId int) (*Task, error) {
+	return c.plannerAction(ctx, ActionStop, taskId, nil)
+}
+
+// RestartTask reopens a completed or cancelled task so that it is scheduled again.
+func (c *Client) RestartTask(ctx context.Context, taskId int) (*Task, error) {
+	return c.plannerAction(ctx, ActionRestart, taskId, nil)
+}
+
+// LogWork records that d was spent on the task, ending at end.
+func (c *Client) LogWork(ctx context.Context, taskId int, d time.Duration, end time.Time) (*Task, error) {
+	minutes := int(d / time.Minute)
+	if minutes <= 0 {
+		return nil, fmt.Errorf("cannot log %s of work, it must be at least a minute", d)
+	}
+
+	query := url.Values{}
+	query.Set("minutes", strconv.Itoa(minutes))
+	if !end.IsZero() {
+		query.Set("end", end.Format(time.RFC3339))
+	}
+	return c.plannerAction(ctx, ActionLogWork, taskId, query)
+}
+
+func (c *Client) plannerAction(ctx context.Context, action PlannerAction, taskId int, query url.Values) (*Task, error) {
+	path := fmt.Sprintf("/api/planner/%s/task/%d", action, taskId)
+	if len(query) > 0 {
+		path = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+
+	var response *PlannerActionResponse
+	err := c.request(ctx, http.MethodPost, path, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil || response.TaskOrHabit == nil {
+		return nil, fmt.Errorf("%s task %d: no task in the response", action, taskId)
+	}
+
+	return response.TaskOrHabit, nil
+}
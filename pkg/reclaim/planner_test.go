@@ -0,0 +1,55 @@
+package reclaim_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func TestClient_PlannerActions(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	task := srv.AddTask(&reclaim.Task{Title: "write tests", TimeChunksRequired: 4, TimeChunksRemaining: 4})
+
+	started, err := client.StartTask(ctx, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.StatusInProgress, started.Status)
+
+	logged, err := client.LogWork(ctx, task.Id, time.Minute*30, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.Chunks(2), logged.TimeChunksSpent)
+	assert.Equal(t, reclaim.Chunks(2), logged.TimeChunksRemaining)
+
+	stopped, err := client.StopTask(ctx, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.StatusScheduled, stopped.Status)
+
+	done, err := client.MarkTaskDone(ctx, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.StatusComplete, done.Status)
+	assert.False(t, done.Finished.IsZero())
+
+	_, err = client.StopTask(ctx, task.Id)
+	assert.True(t, reclaim.IsStatus(err, http.StatusBadRequest))
+
+	reopened, err := client.RestartTask(ctx, task.Id)
+	require.NoError(t, err)
+	assert.Equal(t, reclaim.StatusNew, reopened.Status)
+	assert.Equal(t, reclaim.Chunks(2), reopened.TimeChunksRemaining)
+
+	_, err = client.LogWork(ctx, task.Id, time.Second, time.Now())
+	assert.Error(t, err)
+
+	_, err = client.MarkTaskDone(ctx, task.Id+100)
+	assert.True(t, reclaim.IsNotFound(err))
+}
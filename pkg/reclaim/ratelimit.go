@@ -0,0 +1,29 @@
+package reclaim
+
+import (
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultRateLimit = 5
+	DefaultRateBurst = 10
+)
+
+// WithRateLimit limits the client to requestsPerSecond on average, allowing bursts of up to burst
+// requests. The limit is shared by every goroutine using the client. A requestsPerSecond of zero
+// or less disables rate limiting.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newLimiter(requestsPerSecond, burst)
+	}
+}
+
+func newLimiter(requestsPerSecond float64, burst int) *rate.Limiter {
+	if requestsPerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+}
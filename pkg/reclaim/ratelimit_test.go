@@ -0,0 +1,59 @@
+package reclaim
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RateLimitIsSharedByGoroutines(t *testing.T) {
+	srv, calls := stubServer(t, nil)
+	client := New(WithBaseURL(srv.URL), WithRateLimit(20, 1))
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetTasks(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// the first request uses the burst, the remaining four wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*190)
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls))
+}
+
+func TestClient_RateLimitHonoursContext(t *testing.T) {
+	srv, _ := stubServer(t, nil)
+	client := New(WithBaseURL(srv.URL), WithRateLimit(0.1, 1))
+
+	_, err := client.GetTasks(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err = client.GetTasks(ctx)
+	assert.Error(t, err)
+}
+
+func TestWithRateLimit_Disabled(t *testing.T) {
+	srv, calls := stubServer(t, nil)
+	client := New(WithBaseURL(srv.URL), WithRateLimit(0, 0))
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		_, err := client.GetTasks(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(20), atomic.LoadInt32(calls))
+}
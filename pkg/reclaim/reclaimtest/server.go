@@ -0,0 +1,465 @@
+// Package reclaimtest provides an in-memory fake of the Reclaim API so that
+// reclaim.Client and the CLI commands built on it can be exercised without a network.
+package reclaimtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextId   int
+	tasks    map[int]*reclaim.Task
+	links    []*reclaim.ScheduleLink
+	slots    map[string][]*reclaim.MeetingTime
+	meetings []*reclaim.MeetingRequest
+	schemes  []*reclaim.TimeScheme
+	failures map[string]int
+	logged   map[int]time.Duration
+	counts   map[string]int
+	requests int
+}
+
+// NewServer starts a fake Reclaim API. Callers must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		nextId:   1,
+		tasks:    make(map[int]*reclaim.Task),
+		slots:    make(map[string][]*reclaim.MeetingTime),
+		failures: make(map[string]int),
+		logged:   make(map[int]time.Duration),
+		counts:   make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tasks", s.getTasks)
+	mux.HandleFunc("POST /api/tasks", s.createTask)
+	mux.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	mux.HandleFunc("PUT /api/tasks/{id}", s.putTask)
+	mux.HandleFunc("PATCH /api/tasks/{id}", s.patchTask)
+	mux.HandleFunc("DELETE /api/tasks/{id}", s.deleteTask)
+	mux.HandleFunc("GET /api/scheduling-link", s.getScheduleLinks)
+	mux.HandleFunc("GET /api/scheduling-link/{id}/meeting/availability/V2", s.getAvailability)
+	mux.HandleFunc("POST /api/scheduling-link/{id}/meeting", s.createMeeting)
+	mux.HandleFunc("GET /api/timeschemes", s.getTimeSchemes)
+	mux.HandleFunc("POST /api/planner/{action}/task/{id}", s.plannerAction)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		requestId := s.requests
+		s.counts[r.Method+" "+r.URL.Path]++
+		status, fail := s.failures[r.Method+" "+r.URL.Path]
+		s.mu.Unlock()
+
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", requestId))
+		if fail {
+			writeError(w, status, fmt.Sprintf("injected failure for %s %s", r.Method, r.URL.Path))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// Client returns a reclaim.Client pointed at the fake server.
+func (s *Server) Client(opts ...reclaim.Option) *reclaim.Client {
+	return reclaim.New(append([]reclaim.Option{reclaim.WithBaseURL(s.URL), reclaim.WithAPIKey("test")}, opts...)...)
+}
+
+// AddTask stores a copy of task, assigning an id when it has none, and returns the stored copy.
+func (s *Server) AddTask(task *reclaim.Task) *reclaim.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := *task
+	if t.Id == 0 {
+		t.Id = s.nextId
+	}
+	if t.Id >= s.nextId {
+		s.nextId = t.Id + 1
+	}
+	if t.Status == "" {
+		t.Status = reclaim.StatusNew
+	}
+	if t.Created.IsZero() {
+		t.Created = time.Now()
+	}
+	t.Updated = time.Now()
+	s.tasks[t.Id] = &t
+
+	out := t
+	return &out
+}
+
+// Task returns a copy of the stored task, or nil if it does not exist.
+func (s *Server) Task(id int) *reclaim.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil
+	}
+	out := *t
+	return &out
+}
+
+// Tasks returns copies of every stored task ordered by id.
+func (s *Server) Tasks() []*reclaim.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedTasks(nil)
+}
+
+// AddScheduleLink registers a scheduling link and the meeting slots offered for it.
+func (s *Server) AddScheduleLink(link *reclaim.ScheduleLink, slots ...*reclaim.MeetingTime) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.links = append(s.links, link)
+	s.slots[link.Id] = slots
+}
+
+// AddTimeScheme registers a time scheme, such as the user's working hours.
+func (s *Server) AddTimeScheme(timeScheme *reclaim.TimeScheme) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schemes = append(s.schemes, timeScheme)
+}
+
+// Requests returns how many requests with method were made to path, whatever their query.
+func (s *Server) Requests(method string, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[method+" "+path]
+}
+
+// Logged returns the total work logged on the task, exactly as it was sent.
+func (s *Server) Logged(taskId int) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.logged[taskId]
+}
+
+// Fail makes every request with method to path fail with status until Recover is called, e.g.
+// Fail(http.MethodDelete, "/api/tasks/2", http.StatusForbidden).
+func (s *Server) Fail(method string, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method+" "+path] = status
+}
+
+// Recover undoes Fail for method and path.
+func (s *Server) Recover(method string, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, method+" "+path)
+}
+
+// Meetings returns every meeting request received by the server.
+func (s *Server) Meetings() []*reclaim.MeetingRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*reclaim.MeetingRequest(nil), s.meetings...)
+}
+
+func (s *Server) sortedTasks(statuses []string) []*reclaim.Task {
+	var tasks []*reclaim.Task
+	for _, t := range s.tasks {
+		if len(statuses) > 0 && !contains(statuses, string(t.Status)) {
+			continue
+		}
+		out := *t
+		tasks = append(tasks, &out)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Id < tasks[j].Id
+	})
+	return tasks
+}
+
+func (s *Server) getTasks(w http.ResponseWriter, r *http.Request) {
+	var statuses []string
+	if status := r.URL.Query().Get("status"); status != "" {
+		statuses = strings.Split(status, ",")
+	}
+
+	s.mu.Lock()
+	tasks := s.sortedTasks(statuses)
+	s.mu.Unlock()
+
+	if tasks == nil {
+		tasks = []*reclaim.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var task reclaim.Task
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	task.Id = 0
+	task.TimeChunksRemaining = task.TimeChunksRequired
+
+	writeJSON(w, http.StatusOK, s.AddTask(&task))
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+func (s *Server) putTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	var updated reclaim.Task
+	if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	updated.Id = task.Id
+
+	writeJSON(w, http.StatusOK, s.AddTask(&updated))
+}
+
+func (s *Server) patchTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// round trip through a map so that only the fields present in the patch are changed
+	current, err := json.Marshal(task)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(current, &merged); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for k, v := range patch {
+		merged[k] = v
+	}
+	mergedBytes, err := json.Marshal(merged)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var updated reclaim.Task
+	if err := json.Unmarshal(mergedBytes, &updated); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	updated.Id = task.Id
+
+	writeJSON(w, http.StatusOK, s.AddTask(&updated))
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.tasks, task.Id)
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*reclaim.Task, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid task id %q", r.PathValue("id")))
+		return nil, false
+	}
+
+	task := s.Task(id)
+	if task == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task %d not found", id))
+		return nil, false
+	}
+	return task, true
+}
+
+func (s *Server) getScheduleLinks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	links := append([]*reclaim.ScheduleLink{}, s.links...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, links)
+}
+
+func (s *Server) getTimeSchemes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	schemes := append([]*reclaim.TimeScheme{}, s.schemes...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, schemes)
+}
+
+var plannerStatuses = map[reclaim.PlannerAction]reclaim.TaskStatus{
+	reclaim.ActionDone:    reclaim.StatusComplete,
+	reclaim.ActionStart:   reclaim.StatusInProgress,
+	reclaim.ActionStop:    reclaim.StatusScheduled,
+	reclaim.ActionRestart: reclaim.StatusNew,
+}
+
+func (s *Server) plannerAction(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+
+	action := reclaim.PlannerAction(r.PathValue("action"))
+	if action == reclaim.ActionLogWork {
+		minutes, err := strconv.Atoi(r.URL.Query().Get("minutes"))
+		if err != nil || minutes <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid minutes %q", r.URL.Query().Get("minutes")))
+			return
+		}
+		s.mu.Lock()
+		s.logged[task.Id] += time.Duration(minutes) * time.Minute
+		s.mu.Unlock()
+		chunks, _ := reclaim.ChunksFromDuration(time.Duration(minutes) * time.Minute)
+		task.TimeChunksSpent += chunks
+		task.TimeChunksRemaining = max(task.TimeChunksRemaining-chunks, 0)
+	} else {
+		status, ok := plannerStatuses[action]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("unknown planner action %s", action))
+			return
+		}
+		if !task.Status.CanTransitionTo(status) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("cannot %s a task that is %s", action, task.Status))
+			return
+		}
+		task.Status = status
+		switch status {
+		case reclaim.StatusComplete:
+			task.Finished = time.Now()
+			task.TimeChunksRemaining = 0
+		case reclaim.StatusNew:
+			task.Finished = time.Time{}
+			task.TimeChunksRemaining = max(task.TimeChunksRequired-task.TimeChunksSpent, 0)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, reclaim.PlannerActionResponse{TaskOrHabit: s.AddTask(task)})
+}
+
+func (s *Server) getAvailability(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	slots, ok := s.slots[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("scheduling link %s not found", r.PathValue("id")))
+		return
+	}
+
+	if len(slots) == 0 {
+		start := time.Now().Truncate(time.Hour).Add(time.Hour)
+		slots = []*reclaim.MeetingTime{{StartTime: start, EndTime: start.Add(time.Minute * 30), IsSuggested: true}}
+	}
+
+	var mtr reclaim.MeetingTimeResponse
+	mtr.AvailableTimes.ThirtyMinuteSlots = slots
+	writeJSON(w, http.StatusOK, mtr)
+}
+
+func (s *Server) createMeeting(w http.ResponseWriter, r *http.Request) {
+	linkId := r.PathValue("id")
+
+	var req reclaim.MeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.slots[linkId]
+	if ok {
+		s.meetings = append(s.meetings, &req)
+	}
+	meetingId := fmt.Sprintf("meeting-%d", len(s.meetings))
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("scheduling link %s not found", linkId))
+		return
+	}
+
+	var resp reclaim.MeetingResponse
+	resp.MeetingId = meetingId
+	resp.SchedulingLinkId = linkId
+	resp.Message = req.Message
+	resp.Event.Title = req.Message
+	resp.Event.StartTime = req.Start
+	resp.Event.EndTime = req.End
+	resp.Attendee.Name = req.InviteeName
+	resp.Attendee.Email = req.InviteeEmail
+	resp.ConferenceData.JoinUrl = fmt.Sprintf("%s/join/%s", s.URL, meetingId)
+	resp.ConferenceData.StartTime = req.Start
+	resp.ConferenceData.Topic = req.Message
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"status":  status,
+		"message": message,
+	})
+}
+
+func contains(ss []string, s string) bool {
+	for _, candidate := range ss {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package reclaim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lastBody atomic.Value
+
+var fastRetries = RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 10}
+
+// stubServer fails with the given status codes in order and then returns an empty task list for
+// GET requests or an empty task otherwise.
+// The body of the last request received is stored in lastBody.
+func stubServer(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1))
+		body, _ := io.ReadAll(r.Body)
+		lastBody.Store(string(body))
+		if call <= len(statuses) {
+			for k, v := range headers {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		if r.Method == http.MethodGet {
+			_, _ = fmt.Fprint(w, "[]")
+			return
+		}
+		_, _ = fmt.Fprint(w, "{}")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	srv, calls := stubServer(t, nil, http.StatusBadGateway, http.StatusServiceUnavailable)
+	client := New(WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+
+	tasks, err := client.GetTasks(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	srv, calls := stubServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	client := New(WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+
+	_, err := client.GetTasks(context.Background())
+	assert.True(t, IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+func TestClient_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	srv, calls := stubServer(t, nil, http.StatusBadGateway)
+	client := New(WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+
+	_, err := client.CreateTask(context.Background(), TaskCreateOptions{Title: "title", MinChunkSize: 1, MaxChunkSize: 8, TimeChunksRequired: 1, Priority: P1})
+	assert.True(t, IsStatus(err, http.StatusBadGateway))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestClient_RetriesRateLimitedRequestsWithBody(t *testing.T) {
+	srv, calls := stubServer(t, http.Header{"Retry-After": []string{"0"}}, http.StatusTooManyRequests)
+	client := New(WithBaseURL(srv.URL), WithRetryPolicy(fastRetries))
+
+	_, err := client.SnoozeTask(context.Background(), 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.Contains(t, lastBody.Load(), "snoozeUntil")
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Second * 4}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := policy.backoff(attempt, nil)
+		assert.GreaterOrEqual(t, wait, time.Duration(0))
+		assert.Less(t, wait, time.Second*4)
+	}
+
+	response := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	assert.Equal(t, time.Second*2, policy.backoff(0, response))
+
+	response.Header.Set("Retry-After", "120")
+	assert.Equal(t, time.Second*4, policy.backoff(0, response))
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "3", want: time.Second * 3, ok: true},
+		{value: "-1", ok: false},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute, ok: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClient_StopsRetryingWhenContextIsDone(t *testing.T) {
+	srv, calls := stubServer(t, http.Header{"Retry-After": []string{"60"}}, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	client := New(WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxRetries: 3, MaxBackoff: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetTasks(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second*5)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
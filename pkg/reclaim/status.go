@@ -0,0 +1,91 @@
+package reclaim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type TaskStatus string
+
+const (
+	StatusNew        TaskStatus = "NEW"
+	StatusScheduled  TaskStatus = "SCHEDULED"
+	StatusInProgress TaskStatus = "IN_PROGRESS"
+	StatusComplete   TaskStatus = "COMPLETE"
+	StatusCancelled  TaskStatus = "CANCELLED"
+	StatusArchived   TaskStatus = "ARCHIVED"
+)
+
+// KnownStatuses lists every status this package understands, in lifecycle order.
+var KnownStatuses = []TaskStatus{StatusNew, StatusScheduled, StatusInProgress, StatusComplete, StatusCancelled, StatusArchived}
+
+// OpenStatuses are the statuses of tasks that still need to be worked on.
+var OpenStatuses = []TaskStatus{StatusNew, StatusScheduled, StatusInProgress}
+
+// DefaultStatuses are requested by GetTasks when no statuses are given.
+var DefaultStatuses = []TaskStatus{StatusNew, StatusScheduled, StatusInProgress, StatusComplete}
+
+var transitions = map[TaskStatus][]TaskStatus{
+	StatusNew:        {StatusScheduled, StatusInProgress, StatusComplete, StatusCancelled},
+	StatusScheduled:  {StatusNew, StatusInProgress, StatusComplete, StatusCancelled},
+	StatusInProgress: {StatusNew, StatusScheduled, StatusComplete, StatusCancelled},
+	StatusComplete:   {StatusNew, StatusArchived},
+	StatusCancelled:  {StatusNew, StatusArchived},
+	StatusArchived:   {StatusComplete},
+}
+
+// ParseTaskStatus parses a status case insensitively, returning an error for unknown statuses.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	status := TaskStatus(strings.ToUpper(strings.TrimSpace(s)))
+	if !status.IsKnown() {
+		return "", fmt.Errorf("unknown task status %q, expected one of %s", s, joinStatuses(KnownStatuses))
+	}
+	return status, nil
+}
+
+func (s TaskStatus) IsKnown() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// IsOpen reports whether the task still needs to be worked on. Unknown statuses are not open.
+func (s TaskStatus) IsOpen() bool {
+	for _, open := range OpenStatuses {
+		if s == open {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether the task is finished with, either done, cancelled or archived.
+func (s TaskStatus) IsTerminal() bool {
+	return s == StatusComplete || s == StatusCancelled || s == StatusArchived
+}
+
+// CanTransitionTo reports whether a task can be moved from s to next.
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTask validates that task can move to status and then patches it.
+func (c *Client) TransitionTask(ctx context.Context, task *Task, status TaskStatus) (*Task, error) {
+	if !task.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("task %d cannot move from %s to %s", task.Id, task.Status, status)
+	}
+	return c.PatchTask(ctx, task.Id, TaskPatch{Status: &status})
+}
+
+func joinStatuses(statuses []TaskStatus) string {
+	var ss []string
+	for _, status := range statuses {
+		ss = append(ss, string(status))
+	}
+	return strings.Join(ss, ",")
+}
@@ -0,0 +1,51 @@
+package reclaim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskStatus(t *testing.T) {
+	status, err := ParseTaskStatus(" in_progress ")
+	require.NoError(t, err)
+	assert.Equal(t, StatusInProgress, status)
+
+	_, err = ParseTaskStatus("ON_HOLD")
+	assert.Error(t, err)
+}
+
+func TestTaskStatus_Helpers(t *testing.T) {
+	tests := []struct {
+		status   TaskStatus
+		known    bool
+		open     bool
+		terminal bool
+	}{
+		{status: StatusNew, known: true, open: true},
+		{status: StatusScheduled, known: true, open: true},
+		{status: StatusInProgress, known: true, open: true},
+		{status: StatusComplete, known: true, terminal: true},
+		{status: StatusCancelled, known: true, terminal: true},
+		{status: StatusArchived, known: true, terminal: true},
+		{status: "ON_HOLD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.known, tt.status.IsKnown())
+			assert.Equal(t, tt.open, tt.status.IsOpen())
+			assert.Equal(t, tt.terminal, tt.status.IsTerminal())
+		})
+	}
+}
+
+func TestTaskStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, StatusComplete.CanTransitionTo(StatusArchived))
+	assert.True(t, StatusArchived.CanTransitionTo(StatusComplete))
+	assert.True(t, StatusScheduled.CanTransitionTo(StatusComplete))
+	assert.False(t, StatusNew.CanTransitionTo(StatusArchived))
+	assert.False(t, StatusArchived.CanTransitionTo(StatusInProgress))
+	assert.False(t, TaskStatus("ON_HOLD").CanTransitionTo(StatusNew))
+}
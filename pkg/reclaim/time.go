@@ -0,0 +1,81 @@
+package reclaim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// legacyTimeFormat matches the second precision offsets Reclaim sends for unset dates,
+// e.g. 0000-12-31T23:58:45-00:01:15.
+const legacyTimeFormat = "2006-01-02T15:04:05Z07:00:00"
+
+// NullableTime is a time that Reclaim may send as null, an empty string or a sentinel such as
+// 0000-12-31T23:58:45-00:01:15. All of these decode to a NullableTime that is not Valid and
+// encode back to null.
+type NullableTime struct {
+	time.Time
+	Valid bool
+}
+
+// NewNullableTime returns a valid NullableTime, or an invalid one if t is the zero time.
+func NewNullableTime(t time.Time) NullableTime {
+	return NullableTime{Time: t, Valid: !t.IsZero()}
+}
+
+// Before reports whether t is set and before u.
+func (t NullableTime) Before(u time.Time) bool {
+	return t.Valid && t.Time.Before(u)
+}
+
+// After reports whether t is set and after u.
+func (t NullableTime) After(u time.Time) bool {
+	return t.Valid && t.Time.After(u)
+}
+
+func (t NullableTime) String() string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.String()
+}
+
+func (t NullableTime) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time)
+}
+
+func (t *NullableTime) UnmarshalJSON(b []byte) error {
+	*t = NullableTime{}
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("could not decode %s as a time: %w", string(b), err)
+	}
+	if s == "" {
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		var legacyErr error
+		parsed, legacyErr = time.Parse(legacyTimeFormat, s)
+		if legacyErr != nil {
+			return fmt.Errorf("could not decode %q as a time: %w", s, err)
+		}
+	}
+
+	// anything before year 1 is a placeholder for "no date"
+	if parsed.UTC().Year() < 1 || parsed.IsZero() {
+		return nil
+	}
+
+	*t = NewNullableTime(parsed)
+	return nil
+}
@@ -0,0 +1,65 @@
+package reclaim
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableTime_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    time.Time
+		valid   bool
+		wantErr bool
+	}{
+		{name: "null", json: `null`},
+		{name: "empty", json: `""`},
+		{name: "legacy sentinel", json: `"0000-12-31T23:58:45-00:01:15"`},
+		{name: "zero time", json: `"0001-01-01T00:00:00Z"`},
+		{name: "rfc3339", json: `"2026-11-02T17:00:00Z"`, want: time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC), valid: true},
+		{name: "nanos and offset", json: `"2026-11-02T17:00:00.123+01:00"`, want: time.Date(2026, 11, 2, 16, 0, 0, 123000000, time.UTC), valid: true},
+		{name: "garbage", json: `"next tuesday"`, wantErr: true},
+		{name: "number", json: `12`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got NullableTime
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, got.Valid)
+			if tt.valid {
+				assert.True(t, tt.want.Equal(got.Time), "got %s", got.Time)
+			}
+		})
+	}
+}
+
+func TestNullableTime_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(NullableTime{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+
+	b, err = json.Marshal(NewNullableTime(time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, `"2026-11-02T17:00:00Z"`, string(b))
+}
+
+func TestTask_Due(t *testing.T) {
+	var task Task
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 1, "due": "0000-12-31T23:58:45-00:01:15"}`), &task))
+	assert.False(t, task.Due.Valid)
+	assert.False(t, task.Due.Before(time.Now()))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 1, "due": "2020-01-01T00:00:00Z"}`), &task))
+	assert.True(t, task.Due.Before(time.Now()))
+}
@@ -0,0 +1,140 @@
+package reclaim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxNonWorkingDays bounds the search for a working day so a policy without any hours, or a
+// year of holidays, returns an error instead of looping forever.
+const maxNonWorkingDays = 366
+
+// TimeInterval is a block of time within a day, with Start and End as local times such as 09:00:00.
+type TimeInterval struct {
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Duration float64 `json:"duration"`
+}
+
+// DayHours are the hours available on one day of the week.
+type DayHours struct {
+	Intervals  []TimeInterval `json:"intervals"`
+	StartOfDay string         `json:"startOfDay"`
+	EndOfDay   string         `json:"endOfDay"`
+}
+
+// TimePolicy is the weekly hours of a time scheme, keyed by upper case weekday, e.g. MONDAY.
+// Days missing from DayHours, or without any intervals, are not working days.
+type TimePolicy struct {
+	DayHours    map[string]DayHours `json:"dayHours"`
+	StartOfWeek string              `json:"startOfWeek"`
+	EndOfWeek   string              `json:"endOfWeek"`
+}
+
+// TimeScheme is a named set of hours, such as working or personal hours, that tasks are
+// scheduled in.
+type TimeScheme struct {
+	Id           string     `json:"id"`
+	Title        string     `json:"title"`
+	PolicyType   string     `json:"policyType"`
+	TaskCategory string     `json:"taskCategory"`
+	Policy       TimePolicy `json:"policy"`
+}
+
+// DefaultTimePolicy is 09:00 to 17:00, Monday to Friday. It is used when the user's own working
+// hours cannot be found.
+var DefaultTimePolicy = TimePolicy{DayHours: map[string]DayHours{}}
+
+func init() {
+	for day := time.Monday; day <= time.Friday; day++ {
+		DefaultTimePolicy.DayHours[strings.ToUpper(day.String())] = DayHours{
+			Intervals:  []TimeInterval{{Start: "09:00:00", End: "17:00:00", Duration: 8 * 60 * 60}},
+			StartOfDay: "09:00:00",
+			EndOfDay:   "17:00:00",
+		}
+	}
+}
+
+// Day returns the hours for weekday and whether it is a working day.
+func (p TimePolicy) Day(weekday time.Weekday) (DayHours, bool) {
+	hours, ok := p.DayHours[strings.ToUpper(weekday.String())]
+	return hours, ok && len(hours.Intervals) > 0
+}
+
+// NextWorkingStart returns the first start of a working day after after: later the same day when
+// its working hours have not started yet, otherwise on a following day. Days without hours and any
+// day that falls on the same date as one of holidays are skipped. Times are interpreted in after's
+// location.
+func (p TimePolicy) NextWorkingStart(after time.Time, holidays ...time.Time) (time.Time, error) {
+	midnight := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+	for i := 0; i <= maxNonWorkingDays; i++ {
+		day := midnight.AddDate(0, 0, i)
+		hours, ok := p.Day(day.Weekday())
+		if !ok || isHoliday(day, holidays) {
+			continue
+		}
+
+		start := hours.StartOfDay
+		if start == "" {
+			start = hours.Intervals[0].Start
+		}
+		clock, err := parseClock(start)
+		if err != nil {
+			return time.Time{}, err
+		}
+		dayStart := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+		if dayStart.After(after) {
+			return dayStart, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no working day found in the %d days after %s", maxNonWorkingDays, after.Format("2006-01-02"))
+}
+
+func isHoliday(day time.Time, holidays []time.Time) bool {
+	for _, holiday := range holidays {
+		y, m, d := holiday.Date()
+		if day.Year() == y && day.Month() == m && day.Day() == d {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses a time of day such as 09:00:00 or 09:00.
+func parseClock(s string) (time.Time, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("could not parse %q as a time of day", s)
+}
+
+func (c *Client) GetTimeSchemes(ctx context.Context) ([]*TimeScheme, error) {
+	var timeSchemes []*TimeScheme
+	err := c.request(ctx, http.MethodGet, "/api/timeschemes", nil, &timeSchemes)
+	if err != nil {
+		return nil, err
+	}
+
+	return timeSchemes, nil
+}
+
+// GetWorkTimePolicy returns the policy of the user's working hours time scheme.
+func (c *Client) GetWorkTimePolicy(ctx context.Context) (*TimePolicy, error) {
+	timeSchemes, err := c.GetTimeSchemes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, timeScheme := range timeSchemes {
+		if timeScheme.PolicyType == "WORK" {
+			return &timeScheme.Policy, nil
+		}
+	}
+	return nil, errors.New("no working hours time scheme found")
+}
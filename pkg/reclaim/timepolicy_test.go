@@ -0,0 +1,92 @@
+package reclaim_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func TestTimePolicy_NextWorkingStart(t *testing.T) {
+	// a Friday afternoon
+	friday := time.Date(2026, 10, 30, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		after    time.Time
+		holidays []time.Time
+		want     time.Time
+	}{
+		{"weekday", time.Date(2026, 10, 28, 15, 0, 0, 0, time.UTC), nil, time.Date(2026, 10, 29, 9, 0, 0, 0, time.UTC)},
+		{"before the day starts", time.Date(2026, 10, 28, 7, 0, 0, 0, time.UTC), nil, time.Date(2026, 10, 28, 9, 0, 0, 0, time.UTC)},
+		{"monday morning", time.Date(2026, 11, 2, 6, 0, 0, 0, time.UTC), nil, time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)},
+		{"as the day starts", time.Date(2026, 10, 28, 9, 0, 0, 0, time.UTC), nil, time.Date(2026, 10, 29, 9, 0, 0, 0, time.UTC)},
+		{"holiday morning", time.Date(2026, 11, 2, 6, 0, 0, 0, time.UTC), []time.Time{time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)}, time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC)},
+		{"friday skips the weekend", friday, nil, time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)},
+		{"saturday", time.Date(2026, 10, 31, 10, 0, 0, 0, time.UTC), nil, time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)},
+		{"holiday", friday, []time.Time{time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)}, time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reclaim.DefaultTimePolicy.NextWorkingStart(tt.after, tt.holidays...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTimePolicy_Decode(t *testing.T) {
+	var policy reclaim.TimePolicy
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"dayHours": {
+			"TUESDAY": {"intervals": [{"start": "10:30:00", "end": "18:00:00", "duration": 27000}], "startOfDay": "10:30:00", "endOfDay": "18:00:00"},
+			"SUNDAY": {"intervals": [{"start": "08:00:00", "end": "12:00:00", "duration": 14400}]},
+			"MONDAY": {"intervals": []}
+		},
+		"startOfWeek": "SUNDAY",
+		"endOfWeek": "TUESDAY"
+	}`), &policy))
+
+	_, ok := policy.Day(time.Monday)
+	assert.False(t, ok)
+	_, ok = policy.Day(time.Wednesday)
+	assert.False(t, ok)
+
+	// monday has no intervals so the next working day is tuesday
+	got, err := policy.NextWorkingStart(time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 3, 10, 30, 0, 0, time.UTC), got)
+
+	// sunday has no startOfDay so its first interval is used
+	got, err = policy.NextWorkingStart(time.Date(2026, 11, 3, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 8, 8, 0, 0, 0, time.UTC), got)
+
+	_, err = reclaim.TimePolicy{}.NextWorkingStart(time.Now())
+	assert.Error(t, err)
+}
+
+func TestClient_GetWorkTimePolicy(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	_, err := client.GetWorkTimePolicy(context.Background())
+	assert.Error(t, err)
+
+	srv.AddTimeScheme(&reclaim.TimeScheme{Id: "personal", PolicyType: "PERSONAL"})
+	srv.AddTimeScheme(&reclaim.TimeScheme{Id: "work", PolicyType: "WORK", Policy: reclaim.DefaultTimePolicy})
+
+	policy, err := client.GetWorkTimePolicy(context.Background())
+	require.NoError(t, err)
+	_, ok := policy.Day(time.Friday)
+	assert.True(t, ok)
+	_, ok = policy.Day(time.Saturday)
+	assert.False(t, ok)
+}
@@ -0,0 +1,104 @@
+// Package trash keeps snapshots of deleted tasks on disk so that they can be restored later.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/petetanton/reclaim-cli/pkg/jsondir"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+// ErrNotFound is returned when there is no snapshot for a task.
+var ErrNotFound = errors.New("not found in the trash")
+
+// Entry is a snapshot of a task taken just before it was deleted.
+type Entry struct {
+	// Id is where the snapshot is kept in the trash directory.
+	Id        string        `json:"-"`
+	DeletedAt time.Time     `json:"deletedAt"`
+	Task      *reclaim.Task `json:"task"`
+}
+
+// Trash is a directory of snapshots, one JSON file per deleted task.
+type Trash struct {
+	files *jsondir.Dir
+}
+
+func New(dir string) *Trash {
+	return &Trash{files: jsondir.New(dir)}
+}
+
+// DefaultDir is the trash directory used when none is configured.
+func DefaultDir() (string, error) {
+	return jsondir.ConfigDir("trash")
+}
+
+func (t *Trash) Dir() string {
+	return t.files.Path()
+}
+
+// Save snapshots task.
+func (t *Trash) Save(task *reclaim.Task) (*Entry, error) {
+	entry := &Entry{DeletedAt: time.Now(), Task: task}
+	entry.Id = fmt.Sprintf("%d-%d", task.Id, entry.DeletedAt.UnixNano())
+	if err := t.files.Write(entry.Id, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// List returns every snapshot, most recently deleted first.
+func (t *Trash) List() ([]*Entry, error) {
+	ids, err := t.files.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, id := range ids {
+		entry, err := t.read(id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// Find returns the most recent snapshot of the task with taskId.
+func (t *Trash) Find(taskId int) (*Entry, error) {
+	entries, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Task.Id == taskId {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("task %d %w", taskId, ErrNotFound)
+}
+
+// Remove deletes a snapshot, e.g. once it has been restored.
+func (t *Trash) Remove(entry *Entry) error {
+	return t.files.Remove(entry.Id)
+}
+
+func (t *Trash) read(id string) (*Entry, error) {
+	entry := &Entry{Id: id}
+	if err := t.files.Read(id, entry); err != nil {
+		return nil, fmt.Errorf("could not read trash entry %s: %w", id, err)
+	}
+	if entry.Task == nil {
+		return nil, fmt.Errorf("trash entry %s has no task", id)
+	}
+	return entry, nil
+}
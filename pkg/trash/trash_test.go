@@ -0,0 +1,57 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+func TestTrash(t *testing.T) {
+	trash := New(filepath.Join(t.TempDir(), "trash"))
+
+	entries, err := trash.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = trash.Find(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	first, err := trash.Save(&reclaim.Task{Id: 1, Title: "first", Notes: "keep me"})
+	require.NoError(t, err)
+	second, err := trash.Save(&reclaim.Task{Id: 2, Title: "second"})
+	require.NoError(t, err)
+	again, err := trash.Save(&reclaim.Task{Id: 1, Title: "first again"})
+	require.NoError(t, err)
+
+	entries, err = trash.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{again.Id, second.Id, first.Id}, []string{entries[0].Id, entries[1].Id, entries[2].Id})
+	assert.Equal(t, "keep me", entries[2].Task.Notes)
+
+	found, err := trash.Find(1)
+	require.NoError(t, err)
+	assert.Equal(t, "first again", found.Task.Title)
+
+	require.NoError(t, trash.Remove(found))
+	found, err = trash.Find(1)
+	require.NoError(t, err)
+	assert.Equal(t, "first", found.Task.Title)
+
+	info, err := os.Stat(trash.Dir())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+}
+
+func TestTrash_Corrupt(t *testing.T) {
+	trash := New(t.TempDir())
+	require.NoError(t, os.WriteFile(filepath.Join(trash.Dir(), "1-1.json"), []byte("{"), 0o600))
+
+	_, err := trash.List()
+	assert.Error(t, err)
+}
@@ -0,0 +1,189 @@
+// Package when parses the human friendly times accepted by commands that schedule or snooze
+// tasks, such as "tomorrow 9am", "next monday", "+3d", "eow" or "2026-11-02 14:00".
+package when
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DayStart is the hour used for days given without a time, e.g. "tomorrow".
+	DayStart = 9
+	// DayEnd is the hour used for "eod" and "eow".
+	DayEnd = 17
+)
+
+var absoluteLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+var (
+	offsetPattern    = regexp.MustCompile(`^(?:\+|in )\s*(\d+)\s*([a-z]+)$`)
+	timeOfDayPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+var offsetUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": time.Hour * 24, "day": time.Hour * 24, "days": time.Hour * 24,
+	"w": time.Hour * 24 * 7, "week": time.Hour * 24 * 7, "weeks": time.Hour * 24 * 7,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Parse converts s to a time relative to now, in now's location. It accepts:
+//
+//   - now
+//   - offsets such as +30m, +3d, +1w or in 2 days, added to now
+//   - eod and eow, the end of today or of this week's Friday, rolling over once they have passed
+//   - today, tomorrow, a weekday such as monday or next monday, and next week, optionally followed
+//     by a time of day such as 9am, 2:30pm or 14:00. Days without a time start at DayStart.
+//     A weekday always means the next one after today.
+//   - a time of day on its own, which is today or tomorrow if that time has already passed
+//   - RFC3339 timestamps and dates such as 2026-11-02 or 2026-11-02 14:00
+func Parse(s string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return t, nil
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	lower := strings.Join(strings.Fields(strings.ToLower(trimmed)), " ")
+	if t, ok := parseRelative(lower, now); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("could not parse %q as a time, expected e.g. tomorrow 9am, next monday, +3d, eow or 2026-11-02 14:00", s)
+}
+
+func parseRelative(s string, now time.Time) (time.Time, bool) {
+	switch s {
+	case "now":
+		return now, true
+	case "eod":
+		end := at(now, DayEnd, 0)
+		if !end.After(now) {
+			end = end.AddDate(0, 0, 1)
+		}
+		return end, true
+	case "eow":
+		end := at(nextWeekday(now, time.Friday, true), DayEnd, 0)
+		if !end.After(now) {
+			end = end.AddDate(0, 0, 7)
+		}
+		return end, true
+	}
+
+	if m := offsetPattern.FindStringSubmatch(s); m != nil {
+		unit, ok := offsetUnits[m[2]]
+		n, err := strconv.Atoi(m[1])
+		if !ok || err != nil {
+			return time.Time{}, false
+		}
+		return now.Add(unit * time.Duration(n)), true
+	}
+
+	day, rest, ok := parseDay(s, now)
+	if !ok {
+		// a time on its own means the next time the clock shows it
+		hour, minute, ok := parseTimeOfDay(s)
+		if !ok {
+			return time.Time{}, false
+		}
+		t := at(now, hour, minute)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, true
+	}
+
+	if rest == "" {
+		return at(day, DayStart, 0), true
+	}
+	hour, minute, ok := parseTimeOfDay(strings.TrimPrefix(rest, "at "))
+	if !ok {
+		return time.Time{}, false
+	}
+	return at(day, hour, minute), true
+}
+
+// parseDay parses the day at the start of s, returning the rest of s.
+func parseDay(s string, now time.Time) (day time.Time, rest string, ok bool) {
+	word, rest, _ := strings.Cut(s, " ")
+	switch word {
+	case "today":
+		return now, rest, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), rest, true
+	case "next":
+		word, rest, _ = strings.Cut(rest, " ")
+		if word == "week" {
+			return nextWeekday(now, time.Monday, false), rest, true
+		}
+	}
+
+	if weekday, ok := weekdays[word]; ok {
+		return nextWeekday(now, weekday, false), rest, true
+	}
+	return time.Time{}, "", false
+}
+
+// parseTimeOfDay parses 9am, 9:30 pm or 14:00.
+func parseTimeOfDay(s string) (hour int, minute int, ok bool) {
+	m := timeOfDayPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		// a bare number is ambiguous, 9 could be 9am or a date, so insist on minutes or am/pm
+		if m[2] == "" {
+			return 0, 0, false
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// nextWeekday returns the next weekday after now, or now itself when includeToday is set and
+// now is already that weekday.
+func nextWeekday(now time.Time, weekday time.Weekday, includeToday bool) time.Time {
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
+
+func at(day time.Time, hour int, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,83 @@
+package when
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	// a Wednesday afternoon
+	now := time.Date(2026, 10, 28, 14, 30, 0, 0, london)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", now},
+		{"+30m", now.Add(time.Minute * 30)},
+		{"+2h", now.Add(time.Hour * 2)},
+		{"+3d", now.Add(time.Hour * 24 * 3)},
+		{"+1w", now.Add(time.Hour * 24 * 7)},
+		{"in 1 day", now.Add(time.Hour * 24)},
+		{"in 2 days", now.Add(time.Hour * 24 * 2)},
+		{"In 1 Week", now.Add(time.Hour * 24 * 7)},
+		{"tomorrow", time.Date(2026, 10, 29, 9, 0, 0, 0, london)},
+		{"tomorrow 9am", time.Date(2026, 10, 29, 9, 0, 0, 0, london)},
+		{"tomorrow at 2:15pm", time.Date(2026, 10, 29, 14, 15, 0, 0, london)},
+		{"tomorrow 12am", time.Date(2026, 10, 29, 0, 0, 0, 0, london)},
+		{"tomorrow 12pm", time.Date(2026, 10, 29, 12, 0, 0, 0, london)},
+		{"today 16:45", time.Date(2026, 10, 28, 16, 45, 0, 0, london)},
+		{"4pm", time.Date(2026, 10, 28, 16, 0, 0, 0, london)},
+		{"9am", time.Date(2026, 10, 29, 9, 0, 0, 0, london)},
+		{"friday", time.Date(2026, 10, 30, 9, 0, 0, 0, london)},
+		{"mon 10am", time.Date(2026, 11, 2, 10, 0, 0, 0, london)},
+		{"next monday", time.Date(2026, 11, 2, 9, 0, 0, 0, london)},
+		{"wednesday", time.Date(2026, 11, 4, 9, 0, 0, 0, london)},
+		{"next week", time.Date(2026, 11, 2, 9, 0, 0, 0, london)},
+		{"eod", time.Date(2026, 10, 28, 17, 0, 0, 0, london)},
+		{"eow", time.Date(2026, 10, 30, 17, 0, 0, 0, london)},
+		{"2026-11-02", time.Date(2026, 11, 2, 0, 0, 0, 0, london)},
+		{"2026-11-02 14:00", time.Date(2026, 11, 2, 14, 0, 0, 0, london)},
+		{"2026-11-02T14:00", time.Date(2026, 11, 2, 14, 0, 0, 0, london)},
+		{"2026-11-02T14:00:00Z", time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestParseRollsOver(t *testing.T) {
+	// a Friday evening, after the end of the day and the week
+	now := time.Date(2026, 10, 30, 18, 0, 0, 0, time.UTC)
+
+	eod, err := Parse("eod", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 31, 17, 0, 0, 0, time.UTC), eod)
+
+	eow, err := Parse("eow", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 6, 17, 0, 0, 0, time.UTC), eow)
+
+	friday, err := Parse("friday", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 6, 9, 0, 0, 0, time.UTC), friday)
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 10, 28, 14, 30, 0, 0, time.UTC)
+	for _, in := range []string{"", "soon", "+3", "+3 fortnights", "tomorrow 25:00", "tomorrow 13pm", "tomorrow 9", "next month", "2026-13-01", "monday lunchtime"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := Parse(in, now)
+			assert.Error(t, err)
+		})
+	}
+}
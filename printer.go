@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/petetanton/reclaim-cli/pkg/output"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/trash"
+)
+
+// appEnv holds what app.Before sets up for the commands to use.
+type appEnv struct {
+	client   *reclaim.Client
+	printer  *printer
+	trashDir string
+	holidays []time.Time
+
+	// trash is opened by openTrash the first time a command needs it.
+	trashOnce sync.Once
+	trash     *trash.Trash
+	trashErr  error
+
+	timePolicy *reclaim.TimePolicy
+}
+
+// printer reports the values produced by commands, as log lines by default or by executing the
+// --format template against them. It is safe to use from several goroutines.
+type printer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	tmpl *template.Template
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	p := &printer{w: w}
+	if format == "" {
+		return p, nil
+	}
+
+	tmpl, err := output.ParseTemplate(format)
+	if err != nil {
+		return nil, err
+	}
+	p.tmpl = tmpl
+	return p, nil
+}
+
+// Templated reports whether a --format template was given.
+func (p *printer) Templated() bool {
+	return p.tmpl != nil
+}
+
+// Print writes v using the format template, or logs msg when there is no template.
+func (p *printer) Print(v interface{}, msg string, args ...interface{}) error {
+	if p.tmpl == nil {
+		logrus.Infof(msg, args...)
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := output.WriteTemplate(p.w, p.tmpl, v); err != nil {
+		return fmt.Errorf("could not apply format template: %w", err)
+	}
+	return nil
+}
+
+// PrintAll writes every value using the format template.
+func (p *printer) PrintAll(values ...interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := output.WriteTemplate(p.w, p.tmpl, values...); err != nil {
+		return fmt.Errorf("could not apply format template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_format(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Id: 7, Title: "first"})
+	srv.AddTask(&reclaim.Task{Id: 8, Title: "second"})
+	srv.AddScheduleLink(&reclaim.ScheduleLink{Id: "abc", Title: "1:1"})
+
+	out, err := runAppOutput(t, srv, "--format", "{{.Id}} {{.Title}}", "list")
+	require.NoError(t, err)
+	assert.Equal(t, "7 first\n8 second\n", out)
+
+	out, err = runAppOutput(t, srv, "--format", "{{.Id}} {{.SnoozeUntil.Valid}}", "snooze", "8")
+	require.NoError(t, err)
+	assert.Equal(t, "8 true\n", out)
+
+	out, err = runAppOutput(t, srv, "--format", "{{.Id}}={{.Title}}", "links")
+	require.NoError(t, err)
+	assert.Equal(t, "abc=1:1\n", out)
+
+	_, err = runAppOutput(t, srv, "--format", "{{.Id", "list")
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/petetanton/reclaim-cli/pkg/fuzzy"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+const maxCandidatesShown = 5
+
+// taskResolver finds tasks from the arguments given to a command. The task list is only fetched
+// once, however many arguments are resolved.
+type taskResolver struct {
+	client   *reclaim.Client
+	statuses []reclaim.TaskStatus
+	tasks    []*reclaim.Task
+}
+
+func newTaskResolver(client *reclaim.Client, statuses ...reclaim.TaskStatus) *taskResolver {
+	return &taskResolver{client: client, statuses: statuses}
+}
+
+func (r *taskResolver) list(ctx context.Context) ([]*reclaim.Task, error) {
+	if r.tasks == nil {
+		tasks, err := r.client.GetTasks(ctx, r.statuses...)
+		if err != nil {
+			return nil, err
+		}
+		r.tasks = tasks
+	}
+	return r.tasks, nil
+}
+
+// effectiveStatuses are the statuses resolved tasks must be in, which like GetTasks default to
+// reclaim.DefaultStatuses.
+func (r *taskResolver) effectiveStatuses() []reclaim.TaskStatus {
+	if len(r.statuses) == 0 {
+		return reclaim.DefaultStatuses
+	}
+	return r.statuses
+}
+
+// Resolve accepts, in order of preference, a task id, an exact title, an id prefix, a unique
+// title substring or a fuzzy match of the title.
+func (r *taskResolver) Resolve(ctx context.Context, query string) (*reclaim.Task, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("no task given")
+	}
+
+	id, err := strconv.Atoi(query)
+	isId := err == nil
+	if isId {
+		task, err := r.client.GetTask(ctx, id)
+		if err == nil {
+			if !slices.Contains(r.effectiveStatuses(), task.Status) {
+				return nil, fmt.Errorf("no task matches %q, task %d is %s", query, task.Id, task.Status)
+			}
+			return task, nil
+		}
+		if !reclaim.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	tasks, err := r.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lowerQuery := strings.ToLower(query)
+	matchers := []func(task *reclaim.Task) bool{
+		func(task *reclaim.Task) bool {
+			return strings.EqualFold(task.Title, query)
+		},
+		func(task *reclaim.Task) bool {
+			return isId && strings.HasPrefix(strconv.Itoa(task.Id), query)
+		},
+		func(task *reclaim.Task) bool {
+			return strings.Contains(strings.ToLower(task.Title), lowerQuery)
+		},
+	}
+	for _, matcher := range matchers {
+		var matches []*reclaim.Task
+		for _, task := range tasks {
+			if matcher(task) {
+				matches = append(matches, task)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			return nil, ambiguousTaskError(query, matches)
+		}
+	}
+
+	return r.fuzzy(query, tasks)
+}
+
+func (r *taskResolver) fuzzy(query string, tasks []*reclaim.Task) (*reclaim.Task, error) {
+	type scored struct {
+		task  *reclaim.Task
+		score int
+	}
+	var matches []scored
+	for _, task := range tasks {
+		if score, ok := fuzzy.Match(query, task.Title); ok {
+			matches = append(matches, scored{task: task, score: score})
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no task matches %q", query)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	if len(matches) == 1 || matches[0].score > matches[1].score {
+		return matches[0].task, nil
+	}
+
+	var best []*reclaim.Task
+	for _, match := range matches {
+		if match.score == matches[0].score {
+			best = append(best, match.task)
+		}
+	}
+	return nil, ambiguousTaskError(query, best)
+}
+
+func ambiguousTaskError(query string, tasks []*reclaim.Task) error {
+	var candidates []string
+	for i, task := range tasks {
+		if i == maxCandidatesShown {
+			candidates = append(candidates, fmt.Sprintf("and %d more", len(tasks)-maxCandidatesShown))
+			break
+		}
+		candidates = append(candidates, fmt.Sprintf("%d (%s)", task.Id, task.Title))
+	}
+	return fmt.Errorf("%q matches %d tasks, use the id instead: %s", query, len(tasks), strings.Join(candidates, ", "))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_taskResolver(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Id: 1201, Title: "Review MR !42"})
+	srv.AddTask(&reclaim.Task{Id: 1202, Title: "Review MR !43"})
+	srv.AddTask(&reclaim.Task{Id: 1305, Title: "Write quarterly report"})
+	srv.AddTask(&reclaim.Task{Id: 1306, Title: "report"})
+
+	resolver := newTaskResolver(srv.Client())
+	ctx := context.Background()
+
+	tests := []struct {
+		query   string
+		want    int
+		wantErr string
+	}{
+		{query: "1202", want: 1202},
+		{query: "130", wantErr: "matches 2 tasks"},
+		{query: "1305", want: 1305},
+		{query: "REPORT", want: 1306},
+		{query: "quarterly", want: 1305},
+		{query: "review mr", wantErr: "matches 2 tasks"},
+		{query: "!43", want: 1202},
+		{query: "wqr", want: 1305},
+		{query: "zzz", wantErr: "no task matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			task, err := resolver.Resolve(ctx, tt.query)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, task.Id)
+		})
+	}
+}
+
+func Test_snoozeByTitle(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	task := srv.AddTask(&reclaim.Task{Title: "Write quarterly report"})
+
+	require.NoError(t, runApp(t, srv, "snooze", "quarterly"))
+	assert.True(t, srv.Task(task.Id).SnoozeUntil.Valid)
+}
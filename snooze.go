@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+func snoozeCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "snooze",
+		Description: "Snooze tasks so that they are scheduled at a later date. Quote titles that contain spaces",
+		ArgsUsage:   "[task id or title...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: fmt.Sprintf("when to snooze the tasks until, e.g. %s, tomorrow 9am, next monday, +3d, eow or 2026-11-02 14:00", NEXT_WORKING_MORNING),
+				Value: NEXT_WORKING_MORNING,
+			},
+			&cli.StringFlag{
+				Name:  "for",
+				Usage: "how long to snooze the tasks for, e.g. 2h, 3d or 1w. Cannot be used with --until",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			until, err := snoozeTarget(c, env, time.Now())
+			if err != nil {
+				return err
+			}
+
+			tasks, err := chooseTasks(c, env, "Which tasks would you like to snooze", reclaim.OpenStatuses, func(task *reclaim.Task) bool {
+				return task.Status.IsOpen()
+			})
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, task := range tasks {
+				errs = append(errs, snooze(c.Context, env, task.Id, until))
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func unsnoozeCommand(env *appEnv) *cli.Command {
+	return &cli.Command{
+		Name:        "unsnooze",
+		Description: "Clear the snooze on tasks so that they can be scheduled straight away. Quote titles that contain spaces",
+		ArgsUsage:   "[task id or title...]",
+		Action: func(c *cli.Context) error {
+			now := time.Now()
+			tasks, err := chooseTasks(c, env, "Which tasks would you like to unsnooze", reclaim.OpenStatuses, func(task *reclaim.Task) bool {
+				return task.Status.IsOpen() && task.SnoozeUntil.After(now)
+			})
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, task := range tasks {
+				unsnoozed, err := env.client.UnsnoozeTask(c.Context, task.Id)
+				if err == nil {
+					err = env.printer.Print(unsnoozed, "task %d unsnoozed", unsnoozed.Id)
+				}
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// snoozeTarget works out the time to snooze until from --for or --until.
+func snoozeTarget(c *cli.Context, env *appEnv, now time.Time) (time.Time, error) {
+	if c.IsSet("for") && c.IsSet("until") {
+		return time.Time{}, errors.New("--for and --until cannot be used together")
+	}
+
+	var until time.Time
+	if c.IsSet("for") {
+		d, err := parseAge(c.String("for"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --for: %w", err)
+		}
+		until = now.Add(d)
+	} else {
+		var err error
+		if until, err = env.parseTime(c.Context, c.String("until"), now); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if !until.After(now) {
+		return time.Time{}, fmt.Errorf("cannot snooze until %s as it is in the past", until.Format(displayTimeFormat))
+	}
+	return until, nil
+}
+
+// chooseTasks resolves every argument to a task in one of statuses that matches include, or when
+// there are none asks the user to pick from the tasks in statuses that match include.
+func chooseTasks(c *cli.Context, env *appEnv, question string, statuses []reclaim.TaskStatus, include func(*reclaim.Task) bool) ([]*reclaim.Task, error) {
+	if c.Args().Present() {
+		resolver := newTaskResolver(env.client, statuses...)
+		var tasks []*reclaim.Task
+		for _, arg := range c.Args().Slice() {
+			task, err := resolver.Resolve(c.Context, arg)
+			if err != nil {
+				return nil, err
+			}
+			if !include(task) {
+				return nil, fmt.Errorf("cannot %s task %d %s as it is %s", c.Command.Name, task.Id, task.Title, task.Status)
+			}
+			tasks = append(tasks, task)
+		}
+		return tasks, nil
+	}
+
+	tasks, err := env.client.GetTasks(c.Context, statuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []string
+	byItem := make(map[string]*reclaim.Task)
+	for _, task := range tasks {
+		if !include(task) {
+			continue
+		}
+		item := fmt.Sprintf("%d, %s", task.Id, task.Title)
+		if task.Due.Valid {
+			item = fmt.Sprintf("%s (due %s)", item, task.Due.Local().Format(displayTimeFormat))
+		}
+		if task.SnoozeUntil.Valid {
+			item = fmt.Sprintf("%s (snoozed until %s)", item, task.SnoozeUntil.Local().Format(displayTimeFormat))
+		}
+		items = append(items, item)
+		byItem[item] = task
+	}
+	if len(items) == 0 {
+		logrus.Info("no matching tasks")
+		return nil, nil
+	}
+
+	selected, err := input.AskMultiSelectWithError(question, items)
+	if errors.Is(err, input.ErrNotInteractive) {
+		return nil, fmt.Errorf("give task ids or titles as arguments when not running in a terminal: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var chosen []*reclaim.Task
+	for _, item := range selected {
+		chosen = append(chosen, byItem[item])
+	}
+	return chosen, nil
+}
+
+func snooze(ctx context.Context, env *appEnv, taskId int, until time.Time) error {
+	task, err := env.client.SnoozeTask(ctx, taskId, until)
+	if err != nil {
+		return err
+	}
+	return env.printer.Print(task, "task %d snoozed until %s", task.Id, until.Format(displayTimeFormat))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/petetanton/reclaim-cli/pkg/input"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/reclaim/reclaimtest"
+)
+
+func Test_snoozeMany(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	report := srv.AddTask(&reclaim.Task{Title: "Write quarterly report"})
+	review := srv.AddTask(&reclaim.Task{Title: "Review MR"})
+	other := srv.AddTask(&reclaim.Task{Title: "Something else"})
+
+	require.NoError(t, runApp(t, srv, "snooze", "--for", "2d", "quarterly report", "review"))
+	for _, task := range []*reclaim.Task{report, review} {
+		assert.WithinDuration(t, time.Now().Add(time.Hour*48), srv.Task(task.Id).SnoozeUntil.Time, time.Minute)
+	}
+	assert.False(t, srv.Task(other.Id).SnoozeUntil.Valid)
+
+	assert.Error(t, runApp(t, srv, "snooze", "--for", "2d", "--until", "tomorrow", "review"))
+	assert.Error(t, runApp(t, srv, "snooze", "--for", "a bit", "review"))
+	assert.Error(t, runApp(t, srv, "snooze", "review", "no such task"))
+}
+
+func Test_unsnooze(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	snoozed := srv.AddTask(&reclaim.Task{Title: "snoozed", SnoozeUntil: reclaim.NewNullableTime(time.Now().Add(time.Hour * 24))})
+
+	out, err := runAppOutput(t, srv, "--format", "{{.Id}} {{.SnoozeUntil.Valid}}", "unsnooze", "snoozed")
+	require.NoError(t, err)
+	assert.Equal(t, "1 false\n", out)
+	assert.False(t, srv.Task(snoozed.Id).SnoozeUntil.Valid)
+}
+
+func Test_snoozeClosed(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	complete := srv.AddTask(&reclaim.Task{Title: "done already", Status: reclaim.StatusComplete})
+	archived := srv.AddTask(&reclaim.Task{Title: "long gone", Status: reclaim.StatusArchived, SnoozeUntil: reclaim.NewNullableTime(time.Now().Add(time.Hour * 24))})
+	open := srv.AddTask(&reclaim.Task{Title: "not snoozed"})
+
+	err := runApp(t, srv, "snooze", "--for", "1d", strconv.Itoa(complete.Id))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is COMPLETE")
+	assert.False(t, srv.Task(complete.Id).SnoozeUntil.Valid)
+
+	assert.Error(t, runApp(t, srv, "unsnooze", strconv.Itoa(archived.Id)))
+	assert.True(t, srv.Task(archived.Id).SnoozeUntil.Valid)
+
+	err = runApp(t, srv, "unsnooze", strconv.Itoa(open.Id))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot unsnooze task 3 not snoozed")
+}
+
+func Test_snoozeWithoutTerminal(t *testing.T) {
+	if input.IsInteractive() {
+		t.Skip("stdin is a terminal")
+	}
+
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	srv.AddTask(&reclaim.Task{Title: "open"})
+
+	err := runApp(t, srv, "snooze", "--for", "1d")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "give task ids or titles as arguments")
+
+	// nothing to unsnooze so there is nothing to ask
+	require.NoError(t, runApp(t, srv, "unsnooze"))
+}
+
+func Test_snoozeUntil(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	task := srv.AddTask(&reclaim.Task{Title: "review"})
+
+	require.NoError(t, runApp(t, srv, "snooze", "--until", "+3d", strconv.Itoa(task.Id)))
+	snoozeUntil := srv.Task(task.Id).SnoozeUntil
+	assert.WithinDuration(t, time.Now().Add(time.Hour*24*3), snoozeUntil.Time, time.Minute)
+
+	assert.Error(t, runApp(t, srv, "snooze", "--until", "someday", strconv.Itoa(task.Id)))
+	assert.Error(t, runApp(t, srv, "snooze", "--until", "2020-01-01", strconv.Itoa(task.Id)))
+	assert.True(t, snoozeUntil.Equal(srv.Task(task.Id).SnoozeUntil.Time))
+}
+
+func Test_snoozeNextWorkingMorning(t *testing.T) {
+	srv := reclaimtest.NewServer()
+	defer srv.Close()
+
+	// only tuesdays are working days
+	srv.AddTimeScheme(&reclaim.TimeScheme{PolicyType: "WORK", Policy: reclaim.TimePolicy{DayHours: map[string]reclaim.DayHours{
+		"TUESDAY": {Intervals: []reclaim.TimeInterval{{Start: "10:30:00", End: "18:00:00"}}, StartOfDay: "10:30:00"},
+	}}})
+	task := srv.AddTask(&reclaim.Task{Title: "review"})
+
+	now := time.Now()
+	nextTuesday := now.AddDate(0, 0, (int(time.Tuesday)-int(now.Weekday())+6)%7+1)
+	want := time.Date(nextTuesday.Year(), nextTuesday.Month(), nextTuesday.Day(), 10, 30, 0, 0, time.Local)
+
+	require.NoError(t, runApp(t, srv, "snooze", strconv.Itoa(task.Id)))
+	assert.True(t, want.Equal(srv.Task(task.Id).SnoozeUntil.Time), "want %s, got %s", want, srv.Task(task.Id).SnoozeUntil)
+
+	require.NoError(t, runApp(t, srv, "--holiday", want.Format("2006-01-02"), "snooze", strconv.Itoa(task.Id)))
+	assert.True(t, want.AddDate(0, 0, 7).Equal(srv.Task(task.Id).SnoozeUntil.Time))
+
+	assert.Error(t, runApp(t, srv, "--holiday", "christmas", "snooze", strconv.Itoa(task.Id)))
+}
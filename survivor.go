@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+)
+
+// notesSeparator goes between the notes of merged tasks.
+const notesSeparator = "\n\n"
+
+// survivorStrategy picks which task of a duplicate group is kept.
+type survivorStrategy string
+
+const (
+	// keepFirst keeps the first task in the order the API returned them.
+	keepFirst survivorStrategy = "first"
+	// keepOldest keeps the task that was created first.
+	keepOldest survivorStrategy = "oldest"
+	// keepHighestPriority keeps the task with the highest priority, P1 being the highest.
+	keepHighestPriority survivorStrategy = "highest-priority"
+	// keepMostProgress keeps the task with the most time already spent on it.
+	keepMostProgress survivorStrategy = "most-progress"
+)
+
+var survivorStrategies = []survivorStrategy{keepFirst, keepOldest, keepHighestPriority, keepMostProgress}
+
+func parseSurvivorStrategy(s string) (survivorStrategy, error) {
+	for _, strategy := range survivorStrategies {
+		if strings.EqualFold(strings.TrimSpace(s), string(strategy)) {
+			return strategy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown survivor strategy %q, expected one of %s", s, joinValues(survivorStrategies))
+}
+
+// better reports whether a should be kept over b. Ties keep the task that came first.
+func (s survivorStrategy) better(a *reclaim.Task, b *reclaim.Task) bool {
+	switch s {
+	case keepOldest:
+		return a.Created.Before(b.Created)
+	case keepHighestPriority:
+		return priorityRank(a.Priority) < priorityRank(b.Priority)
+	case keepMostProgress:
+		return a.TimeChunksSpent > b.TimeChunksSpent
+	}
+	return false
+}
+
+// pick returns the index of the task to keep.
+func (s survivorStrategy) pick(tasks []*reclaim.Task) int {
+	best := 0
+	for i := 1; i < len(tasks); i++ {
+		if s.better(tasks[i], tasks[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// priorityRank orders priorities from P1 as 1 down to tasks without a known priority last.
+func priorityRank(priority string) int {
+	for i, p := range []reclaim.TaskPriority{reclaim.P1, reclaim.P2, reclaim.P3, reclaim.P4} {
+		if strings.EqualFold(priority, string(p)) {
+			return i + 1
+		}
+	}
+	return 5
+}
+
+// mergeStrategy is how a duplicate group is combined into the survivor.
+type mergeStrategy struct {
+	keep survivorStrategy
+	// notes appends the notes of the duplicates to the survivor's.
+	notes bool
+	// earliestDue gives the survivor the earliest due date in the group.
+	earliestDue bool
+}
+
+var defaultMergeStrategy = mergeStrategy{keep: keepFirst}
+
+func newMergeStrategy(c *cli.Context) (mergeStrategy, error) {
+	keep, err := parseSurvivorStrategy(c.String("keep"))
+	if err != nil {
+		return mergeStrategy{}, err
+	}
+	return mergeStrategy{keep: keep, notes: c.Bool("merge-notes"), earliestDue: c.Bool("earliest-due")}, nil
+}
+
+// mergeNotes appends every distinct, non empty note of duplicates to the survivor's notes.
+func mergeNotes(survivor *reclaim.Task, duplicates []*reclaim.Task) string {
+	notes := strings.TrimSpace(survivor.Notes)
+	for _, duplicate := range duplicates {
+		note := strings.TrimSpace(duplicate.Notes)
+		if note == "" || strings.Contains(notes, note) {
+			continue
+		}
+		if notes != "" {
+			notes += notesSeparator
+		}
+		notes += note
+	}
+	return notes
+}
+
+// earliestDue returns the earliest due date of tasks, which is invalid if none have one.
+func earliestDue(tasks []*reclaim.Task) reclaim.NullableTime {
+	var due reclaim.NullableTime
+	for _, task := range tasks {
+		if task.Due.Valid && (!due.Valid || task.Due.Before(due.Time)) {
+			due = task.Due
+		}
+	}
+	return due
+}
+
+func joinValues[T ~string](values []T) string {
+	var ss []string
+	for _, v := range values {
+		ss = append(ss, string(v))
+	}
+	return strings.Join(ss, ", ")
+}
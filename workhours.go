@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/petetanton/reclaim-cli/pkg/reclaim"
+	"github.com/petetanton/reclaim-cli/pkg/when"
+)
+
+// parseTime parses the times accepted by when.Parse, plus NEXT_WORKING_MORNING which is the start
+// of the next working day in the user's working hours, skipping --holiday dates.
+func (env *appEnv) parseTime(ctx context.Context, s string, now time.Time) (time.Time, error) {
+	switch strings.Join(strings.Fields(strings.ToLower(s)), " ") {
+	case NEXT_WORKING_MORNING, "next working day", "nwm":
+		return env.workTimePolicy(ctx).NextWorkingStart(now, env.holidays...)
+	}
+	return when.Parse(s, now)
+}
+
+// workTimePolicy fetches the user's working hours once, falling back to
+// reclaim.DefaultTimePolicy when they cannot be found.
+func (env *appEnv) workTimePolicy(ctx context.Context) *reclaim.TimePolicy {
+	if env.timePolicy != nil {
+		return env.timePolicy
+	}
+
+	policy, err := env.client.GetWorkTimePolicy(ctx)
+	if err != nil {
+		logrus.Warnf("could not get your working hours, assuming 09:00 to 17:00 Monday to Friday: %v", err)
+		policy = &reclaim.DefaultTimePolicy
+	}
+	env.timePolicy = policy
+	return policy
+}
+
+// parseHolidays parses --holiday dates such as 2026-12-25 as local dates.
+func parseHolidays(values []string) ([]time.Time, error) {
+	var holidays []time.Time
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			holiday, err := time.ParseInLocation("2006-01-02", s, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("invalid holiday %q, expected a date such as 2026-12-25", s)
+			}
+			holidays = append(holidays, holiday)
+		}
+	}
+	return holidays, nil
+}